@@ -2,16 +2,22 @@ package main
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/muesli/termenv"
+	"github.com/pkg/errors"
 	"github.com/schwarzit/go-template/pkg/gotemplate"
 	"github.com/spf13/cobra"
 )
 
+var errExplainWithoutConfig = errors.New("--explain requires the values to be loaded with --config")
+
 func buildNewCommand(output *termenv.Output, gt *gotemplate.GT) *cobra.Command {
 	var (
-		configFile string
-		opts       gotemplate.NewRepositoryOptions
+		configFile      string
+		templateVersion string
+		explain         bool
+		opts            gotemplate.NewRepositoryOptions
 	)
 
 	underline := output.String().Underline().Styled
@@ -38,20 +44,53 @@ This defines the parameters as key value pairs.
 To get further information look at the flag's documentation.
 `, underline("Interactive Mode"), underline("File Mode")),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// explaining the options should not require answering all prompts first
+			if explain && configFile == "" {
+				return errExplainWithoutConfig
+			}
+
 			if err := opts.Validate(); err != nil {
 				return err
 			}
 
+			if templateVersion != "" {
+				source, err := gotemplate.NewGitReleaseTemplateSource(templateVersion)
+				if err != nil {
+					return err
+				}
+
+				if err := gt.UseTemplateSource(source); err != nil {
+					return err
+				}
+			}
+
+			if configFile == "" {
+				recoveryFile, err := gotemplate.DefaultRecoveryFile()
+				if err != nil {
+					return err
+				}
+
+				gt.RecoveryFile = recoveryFile
+			}
+
 			configValues, err := getValues(gt, configFile)
 			if err != nil {
 				return err
 			}
 
 			opts.OptionValues = configValues
+			// values from a config file are not confirmed to keep support for non-interactive usage
+			opts.Confirm = configFile == ""
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return gt.InitNewProject(&opts)
+			if explain {
+				printExplanations(cmd.OutOrStdout(), gt.ExplainOptions(opts.OptionValues))
+				return nil
+			}
+
+			_, err := gt.InitNewProject(&opts)
+			return err
 		},
 	}
 
@@ -71,6 +110,7 @@ base:
   appName: somecli
   moduleName: github.com/some-user/some-project
   golangciVersion: 1.42.1
+  layout: standard
 extensions:
   grpc:
     base: true
@@ -83,9 +123,52 @@ extensions:
 		`Output directory for the newly created project folder.
 `)
 
+	cmd.Flags().StringVar(
+		&templateVersion,
+		"templateVersion", "",
+		`Render the template of the given go/template release tag (e.g. "v0.3.0") instead of the embedded one.
+The release is downloaded once and cached afterwards.
+`)
+
+	cmd.Flags().BoolVarP(
+		&opts.AssumeYes,
+		"yes", "y", false,
+		`Skip the confirmation of the values before generating the project in interactive mode.
+`)
+
+	cmd.Flags().StringVarP(
+		&opts.ArchiveFile,
+		"archive", "a", "",
+		`Write the project into a .tar.gz archive at the given path instead of the output directory.
+`)
+
+	cmd.Flags().BoolVar(
+		&opts.IncludeGit,
+		"includeGit", false,
+		`Initialize a git repository in the project when writing an archive.
+`)
+
+	cmd.Flags().BoolVar(
+		&explain,
+		"explain", false,
+		`Show for every option whether it's displayed with the values of the config file and why,
+instead of generating the project. Requires --config.
+`)
+
 	return cmd
 }
 
+func printExplanations(w io.Writer, explanations []gotemplate.OptionExplanation) {
+	for _, explanation := range explanations {
+		if explanation.Displayed {
+			fmt.Fprintf(w, "shown   %s\n", explanation.Name)
+			continue
+		}
+
+		fmt.Fprintf(w, "hidden  %s: %s\n", explanation.Name, explanation.Reason)
+	}
+}
+
 func getValues(gt *gotemplate.GT, configFile string) (*gotemplate.OptionValues, error) {
 	if configFile != "" {
 		return gt.LoadConfigValuesFromFile(configFile)
@@ -3,5 +3,5 @@ package gotemplate
 
 import "embed"
 
-//go:embed _template _template/.azure-pipelines.yml _template/.dockerignore _template/.editorconfig _template/.githooks _template/.github _template/.gitignore _template/.gitlab-ci.yml _template/.golangci.yml
+//go:embed _template _template/.azure-pipelines.yml _template/.dockerignore _template/.editorconfig _template/.githooks _template/.github _template/.gitignore _template/.gitlab-ci.yml _template/.golangci.yml _template/.pre-commit-config.yaml _template/.release-please-manifest.json _template/.vscode
 var FS embed.FS
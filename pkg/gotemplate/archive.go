@@ -0,0 +1,98 @@
+package gotemplate
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const gitDir = ".git"
+
+// writeArchive writes the directory dir into a gzipped tar archive at archiveFile.
+// All entries are prefixed with the base name of dir.
+// The .git directory is only included if includeGit is set.
+func writeArchive(dir, archiveFile string, includeGit bool) (err error) {
+	file, err := os.Create(archiveFile)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			// ignore error to not overwrite original error
+			_ = os.Remove(archiveFile)
+		}
+	}()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	baseDir := filepath.Dir(dir)
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && d.Name() == gitDir && !includeGit {
+			return fs.SkipDir
+		}
+
+		return addToArchive(tarWriter, baseDir, path, d)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
+
+func addToArchive(tarWriter *tar.Writer, baseDir, path string, d fs.DirEntry) error {
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+
+	name, err := filepath.Rel(baseDir, path)
+	if err != nil {
+		return err
+	}
+
+	header.Name = filepath.ToSlash(name)
+	if d.IsDir() {
+		header.Name += "/"
+	}
+
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(tarWriter, file)
+
+	return err
+}
@@ -0,0 +1,64 @@
+package gotemplate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// ValidateOptionValues checks that the values fulfill all Assertions of the options.
+// All failed assertions are collected and returned wrapped in ErrAssertionFailed.
+func (gt *GT) ValidateOptionValues(optionValues *OptionValues) error {
+	return validateAssertions(gt.Options.Assertions, gt.FuncMap, optionValues)
+}
+
+func validateAssertions(assertions []Assertion, baseFuncMap template.FuncMap, optionValues *OptionValues) error {
+	funcMap := template.FuncMap{}
+	for name, f := range baseFuncMap {
+		funcMap[name] = f
+	}
+	funcMap["implies"] = implies
+
+	var failed []string
+	for _, assertion := range assertions {
+		ok, err := evaluateAssertion(assertion, funcMap, optionValues)
+		if err != nil {
+			return errors.Wrapf(err, "assertion %q", assertion.Condition)
+		}
+
+		if !ok {
+			failed = append(failed, assertion.Message)
+		}
+	}
+
+	if len(failed) > 0 {
+		return errors.Wrap(ErrAssertionFailed, strings.Join(failed, "; "))
+	}
+
+	return nil
+}
+
+func evaluateAssertion(assertion Assertion, funcMap template.FuncMap, optionValues *OptionValues) (bool, error) {
+	tmpl, err := template.New("").Funcs(funcMap).Parse(fmt.Sprintf("{{ if %s }}true{{ end }}", assertion.Condition))
+	if err != nil {
+		return false, err
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, optionValues); err != nil {
+		return false, err
+	}
+
+	return buffer.String() == "true", nil
+}
+
+// implies returns false only if condition is truthy and consequence is not.
+func implies(condition, consequence interface{}) bool {
+	conditionTrue, _ := template.IsTrue(condition)
+	consequenceTrue, _ := template.IsTrue(consequence)
+
+	return !conditionTrue || consequenceTrue
+}
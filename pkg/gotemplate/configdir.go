@@ -0,0 +1,71 @@
+package gotemplate
+
+import (
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// ValidateConfigDir validates all config files (.yml and .yaml) in dir and its subdirectories
+// in the same way LoadConfigValuesFromFile does.
+// The files are validated concurrently by a bounded number of workers.
+// The result contains an entry for every config file with its path relative to dir,
+// the value is nil for valid files.
+// If dir itself can't be read the error is returned for dir.
+func (gt *GT) ValidateConfigDir(dir string) map[string]error {
+	var files []string
+	err := filepath.WalkDir(dir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if ext := filepath.Ext(filePath); !d.IsDir() && (ext == ".yml" || ext == ".yaml") {
+			files = append(files, filePath)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return map[string]error{dir: err}
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		jobs    = make(chan string)
+		results = make(map[string]error, len(files))
+	)
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(files) {
+		workers = len(files)
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range jobs {
+				_, err := gt.LoadConfigValuesFromFile(file)
+
+				name, relErr := filepath.Rel(dir, file)
+				if relErr != nil {
+					name = file
+				}
+
+				mu.Lock()
+				results[name] = err
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, file := range files {
+		jobs <- file
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
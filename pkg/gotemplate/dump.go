@@ -0,0 +1,126 @@
+package gotemplate
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	dumpIndent = 2
+	// maxPlainFloat is the limit up to which floats are formatted without exponent.
+	maxPlainFloat = 1e21
+)
+
+// DumpOptionValues writes the values as YAML to w.
+// The output has the same format as the values files that can be loaded with LoadConfigValuesFromFile.
+// Before anything is written it's verified that loading the output results in the same values.
+// ErrLossyDump is returned if that's not the case, e.g. for value types that YAML can't represent.
+func DumpOptionValues(w io.Writer, values *OptionValues) error {
+	buffer := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(buffer)
+	encoder.SetIndent(dumpIndent)
+
+	if err := encoder.Encode(values); err != nil {
+		return err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	if err := verifyRoundTrip(values, buffer.Bytes()); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buffer.Bytes())
+
+	return err
+}
+
+// MarshalYAML makes sure that all values are decoded with the same type again.
+// Floats without fractional part would otherwise be written like ints.
+func (o OptionNameToValue) MarshalYAML() (interface{}, error) {
+	values := make(map[string]interface{}, len(o))
+	for name, value := range o {
+		f, ok := value.(float64)
+		if !ok || f != math.Trunc(f) || math.Abs(f) >= maxPlainFloat {
+			values[name] = value
+			continue
+		}
+
+		values[name] = &yaml.Node{
+			Kind:  yaml.ScalarNode,
+			Tag:   "!!float",
+			Value: strconv.FormatFloat(f, 'f', 1, 64),
+		}
+	}
+
+	return values, nil
+}
+
+// verifyRoundTrip checks that dumped decodes to the same values (including their types).
+// Nil and empty maps are treated as equal.
+func verifyRoundTrip(values *OptionValues, dumped []byte) error {
+	var loaded OptionValues
+	if err := yaml.Unmarshal(dumped, &loaded); err != nil {
+		return errors.Wrap(ErrLossyDump, err.Error())
+	}
+
+	if err := compareOptionNameToValues("", values.Base, loaded.Base); err != nil {
+		return err
+	}
+
+	if len(values.Extensions) != len(loaded.Extensions) {
+		return errors.Wrap(ErrLossyDump, "extensions")
+	}
+
+	categories := make([]string, 0, len(values.Extensions))
+	for category := range values.Extensions {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	for _, category := range categories {
+		loadedValues, ok := loaded.Extensions[category]
+		if !ok {
+			return errors.Wrap(ErrLossyDump, category)
+		}
+
+		if err := compareOptionNameToValues(category+".", values.Extensions[category], loadedValues); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func compareOptionNameToValues(prefix string, original, loaded OptionNameToValue) error {
+	if len(original) != len(loaded) {
+		return errors.Wrap(ErrLossyDump, fmt.Sprintf("%snumber of values changed", prefix))
+	}
+
+	names := make([]string, 0, len(original))
+	for name := range original {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value, loadedValue := original[name], loaded[name]
+		if !reflect.DeepEqual(value, loadedValue) {
+			return errors.Wrap(ErrLossyDump, fmt.Sprintf(
+				"%s%s: %v (%T) became %v (%T)", prefix, name, value, value, loadedValue, loadedValue,
+			))
+		}
+	}
+
+	return nil
+}
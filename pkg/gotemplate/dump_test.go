@@ -0,0 +1,87 @@
+package gotemplate_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/schwarzit/go-template/pkg/gotemplate"
+)
+
+func TestDumpOptionValues(t *testing.T) {
+	t.Run("loaded values round-trip", func(t *testing.T) {
+		gt := gotemplate.GT{
+			Options: &gotemplate.Options{
+				Base: []gotemplate.Option{
+					gotemplate.NewOption("projectName", "description", gotemplate.StaticValue("project")),
+					gotemplate.NewOption("version", "description", gotemplate.StaticValue("")),
+				},
+				Extensions: []gotemplate.Category{
+					{
+						Name: "ci",
+						Options: []gotemplate.Option{
+							gotemplate.NewOption("provider", "description", gotemplate.StaticValue(1)),
+							gotemplate.NewOption("enabled", "description", gotemplate.StaticValue(false)),
+							gotemplate.NewOption("answer", "description", gotemplate.StaticValue("")),
+						},
+					},
+				},
+			},
+		}
+
+		values, err := loadValueFromTestFile(t, &gt, `---
+base:
+    projectName: someProject
+    version: "1.20"
+extensions:
+    ci:
+        provider: 2
+        enabled: yes
+        answer: "yes"
+`)
+		require.NoError(t, err)
+
+		dumped := &bytes.Buffer{}
+		require.NoError(t, gotemplate.DumpOptionValues(dumped, values))
+
+		reloaded, err := loadValueFromTestFile(t, &gt, dumped.String())
+		require.NoError(t, err)
+		require.Equal(t, values, reloaded)
+	})
+
+	t.Run("preserves types", func(t *testing.T) {
+		values := &gotemplate.OptionValues{
+			Base: gotemplate.OptionNameToValue{
+				"int":          42,
+				"bool":         true,
+				"string":       "value",
+				"numberString": "1",
+				"boolString":   "true",
+				"nullString":   "null",
+				"float":        2.0,
+			},
+			Extensions: map[string]gotemplate.OptionNameToValue{},
+		}
+
+		dumped := &bytes.Buffer{}
+		require.NoError(t, gotemplate.DumpOptionValues(dumped, values))
+
+		var reloaded gotemplate.OptionValues
+		require.NoError(t, yaml.Unmarshal(dumped.Bytes(), &reloaded))
+		require.Equal(t, values, &reloaded)
+	})
+
+	t.Run("error if values can't be represented", func(t *testing.T) {
+		values := &gotemplate.OptionValues{
+			Base: gotemplate.OptionNameToValue{"int64": int64(42)},
+		}
+
+		dumped := &bytes.Buffer{}
+		err := gotemplate.DumpOptionValues(dumped, values)
+		require.ErrorIs(t, err, gotemplate.ErrLossyDump)
+		require.Contains(t, err.Error(), "int64")
+		require.Empty(t, dumped.String(), "nothing should be written")
+	})
+}
@@ -0,0 +1,208 @@
+package gotemplate
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const editorConfigFile = ".editorconfig"
+
+// editorConfig contains the sections of an .editorconfig file in the order they are defined in.
+// Only the properties needed to normalize rendered files are evaluated.
+// See https://editorconfig.org for the format.
+type editorConfig struct {
+	sections []editorConfigSection
+}
+
+type editorConfigSection struct {
+	pattern    *regexp.Regexp
+	properties map[string]string
+}
+
+// loadEditorConfig reads the .editorconfig in the root of the template.
+// If the template does not contain one nil is returned.
+func loadEditorConfig(templateFS fs.FS) (*editorConfig, error) {
+	fileBytes, err := fs.ReadFile(templateFS, editorConfigFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return parseEditorConfig(fileBytes)
+}
+
+func parseEditorConfig(data []byte) (*editorConfig, error) {
+	config := &editorConfig{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			pattern, err := editorConfigGlobToRegexp(line[1 : len(line)-1])
+			if err != nil {
+				return nil, err
+			}
+
+			config.sections = append(config.sections, editorConfigSection{
+				pattern:    pattern,
+				properties: map[string]string{},
+			})
+
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		// properties before the first section (e.g. root) don't apply to any files
+		if !ok || len(config.sections) == 0 {
+			continue
+		}
+
+		section := config.sections[len(config.sections)-1]
+		section.properties[strings.ToLower(strings.TrimSpace(key))] = strings.ToLower(strings.TrimSpace(value))
+	}
+
+	return config, scanner.Err()
+}
+
+// editorConfigGlobToRegexp converts the glob of a section into a regular expression.
+// Globs without a "/" match files in any directory.
+func editorConfigGlobToRegexp(glob string) (*regexp.Regexp, error) { //nolint:cyclop // one case per glob token
+	var expr strings.Builder
+
+	if strings.Contains(glob, "/") {
+		expr.WriteString("^")
+		glob = strings.TrimPrefix(glob, "/")
+	} else {
+		expr.WriteString("^(?:.*/)?")
+	}
+
+	braceDepth := 0
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				expr.WriteString(".*")
+				i++
+				continue
+			}
+			expr.WriteString("[^/]*")
+		case '?':
+			expr.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				expr.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end
+		case '{':
+			braceDepth++
+			expr.WriteString("(?:")
+		case '}':
+			if braceDepth == 0 {
+				expr.WriteString(`\}`)
+				continue
+			}
+			braceDepth--
+			expr.WriteString(")")
+		case ',':
+			if braceDepth == 0 {
+				expr.WriteString(",")
+				continue
+			}
+			expr.WriteString("|")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	expr.WriteString("$")
+
+	return regexp.Compile(expr.String())
+}
+
+// properties returns the properties that apply to the file at filePath (relative to the project's root).
+// Properties of later sections take precedence.
+func (c *editorConfig) properties(filePath string) map[string]string {
+	properties := map[string]string{}
+	for _, section := range c.sections {
+		if !section.pattern.MatchString(filePath) {
+			continue
+		}
+
+		for key, value := range section.properties {
+			properties[key] = value
+		}
+	}
+
+	return properties
+}
+
+// normalize applies the indent_style, trim_trailing_whitespace and insert_final_newline
+// properties that match filePath to content.
+// Files that look binary are returned unchanged.
+func (c *editorConfig) normalize(filePath string, content []byte) []byte {
+	if len(content) == 0 || bytes.IndexByte(content, 0) >= 0 {
+		return content
+	}
+
+	properties := c.properties(filePath)
+
+	indentSize, err := strconv.Atoi(properties["indent_size"])
+	if err != nil {
+		// without a (valid) size indentation can't be converted
+		indentSize = 0
+	}
+
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		if indentSize > 0 {
+			line = convertIndent(line, properties["indent_style"], indentSize)
+		}
+
+		if properties["trim_trailing_whitespace"] == "true" {
+			line = strings.TrimRight(line, " \t")
+		}
+
+		lines[i] = line
+	}
+
+	normalized := strings.Join(lines, "\n")
+	if properties["insert_final_newline"] == "true" && !strings.HasSuffix(normalized, "\n") {
+		normalized += "\n"
+	}
+
+	return []byte(normalized)
+}
+
+// convertIndent converts the leading indentation of line to the given style.
+func convertIndent(line, style string, size int) string {
+	content := strings.TrimLeft(line, " \t")
+	indent := line[:len(line)-len(content)]
+
+	switch style {
+	case "space":
+		return strings.ReplaceAll(indent, "\t", strings.Repeat(" ", size)) + content
+	case "tab":
+		return strings.ReplaceAll(indent, strings.Repeat(" ", size), "\t") + content
+	default:
+		return line
+	}
+}
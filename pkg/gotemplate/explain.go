@@ -0,0 +1,60 @@
+package gotemplate
+
+import "fmt"
+
+// OptionExplanation describes whether an option is shown in interactive mode and why.
+type OptionExplanation struct {
+	// Name is the name of the option, extension options are named "<category>.<option>".
+	Name      string
+	Displayed bool
+	// UnmetDependency is the name of the option the option depends on that is not enabled, if any.
+	UnmetDependency string
+	// Reason describes why the option is not displayed. It's empty for displayed options.
+	Reason string
+}
+
+// ExplainOptions evaluates for every option whether it would be displayed with the given values
+// and which category or dependency hides it otherwise.
+// This helps to debug the ShouldDisplay and DependsOn configuration of options.
+func (gt *GT) ExplainOptions(values *OptionValues) []OptionExplanation {
+	var explanations []OptionExplanation
+	for i := range gt.Options.Base {
+		explanations = append(explanations, explainOption(gt.Options.Base[i].Name(), &gt.Options.Base[i], values))
+	}
+
+	for _, category := range gt.Options.Extensions {
+		for i := range category.Options {
+			name := fmt.Sprintf("%s.%s", category.Name, category.Options[i].Name())
+			if !category.shouldDisplay(values) {
+				explanations = append(explanations, OptionExplanation{
+					Name:   name,
+					Reason: fmt.Sprintf("category %s is not displayed", category.Name),
+				})
+				continue
+			}
+
+			explanations = append(explanations, explainOption(name, &category.Options[i], values))
+		}
+	}
+
+	return explanations
+}
+
+func explainOption(name string, option *Option, values *OptionValues) OptionExplanation {
+	if dependency, unmet := option.UnmetDependency(values); unmet {
+		return OptionExplanation{
+			Name:            name,
+			UnmetDependency: dependency,
+			Reason:          fmt.Sprintf("depends on %s which is not enabled", dependency),
+		}
+	}
+
+	if !option.ShouldDisplay(values) {
+		return OptionExplanation{
+			Name:   name,
+			Reason: "ShouldDisplay is false for the current values",
+		}
+	}
+
+	return OptionExplanation{Name: name, Displayed: true}
+}
@@ -0,0 +1,143 @@
+package gotemplate
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// GlobalConfigDirEnv can be set to override the directory the global config is loaded from.
+	GlobalConfigDirEnv = "GT_CONFIG_DIR"
+	globalConfigDir    = "go-template"
+	globalConfigFile   = "config.yml"
+)
+
+// GlobalConfigPath returns the path of the global config file.
+// By default it's located in the user's config dir (e.g. $XDG_CONFIG_HOME/go-template/config.yml),
+// which can be overridden by setting GT_CONFIG_DIR.
+func GlobalConfigPath() (string, error) {
+	if dir := os.Getenv(GlobalConfigDirEnv); dir != "" {
+		return filepath.Join(dir, globalConfigFile), nil
+	}
+
+	userConfigDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(userConfigDir, globalConfigDir, globalConfigFile), nil
+}
+
+// loadGlobalOptionValues loads the option values from the global config file.
+// The global config has the same format as any other values file.
+// If there's no global config empty OptionValues are returned.
+func loadGlobalOptionValues() (*OptionValues, error) {
+	configPath, err := GlobalConfigPath()
+	if err != nil {
+		return NewOptionValues(), nil //nolint:nilerr // without a config dir there's no global config
+	}
+
+	fileBytes, err := os.ReadFile(configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewOptionValues(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	optionValues := NewOptionValues()
+	if err := yaml.Unmarshal(fileBytes, optionValues); err != nil {
+		return nil, err
+	}
+
+	return optionValues, nil
+}
+
+// loadOptions returns the options with defaults taken from the global config and the global values themselves.
+func (gt *GT) loadOptions() (*Options, *OptionValues, error) {
+	globalValues, err := loadGlobalOptionValues()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return gt.Options.withDefaults(globalValues), globalValues, nil
+}
+
+// withDefaults returns a copy of the options where all options that have a value in defaults
+// use that value as default instead.
+// The original default is still used if the types of the values don't match.
+func (o *Options) withDefaults(defaults *OptionValues) *Options {
+	options := &Options{
+		Base:       make([]Option, len(o.Base)),
+		Extensions: make([]Category, len(o.Extensions)),
+		Assertions: o.Assertions,
+	}
+
+	for i, option := range o.Base {
+		options.Base[i] = option.withDefault(defaults.Base)
+	}
+
+	for i, category := range o.Extensions {
+		options.Extensions[i] = category
+		options.Extensions[i].Options = make([]Option, len(category.Options))
+		for j, option := range category.Options {
+			options.Extensions[i].Options[j] = option.withDefault(defaults.Extensions[category.Name])
+		}
+	}
+
+	return options
+}
+
+func (s Option) withDefault(defaults OptionNameToValue) Option {
+	defaultValue, ok := defaults[s.Name()]
+	if !ok {
+		return s
+	}
+
+	originalDefault := s.defaultValue
+	s.defaultValue = DynamicValue(func(vals *OptionValues) interface{} {
+		originalValue := originalDefault.Value(vals)
+		if reflect.TypeOf(originalValue) != reflect.TypeOf(defaultValue) {
+			return originalValue
+		}
+
+		return defaultValue
+	})
+
+	return s
+}
+
+// setDefaults sets all values of defaults that are not yet set in v.
+func (v *OptionValues) setDefaults(defaults *OptionValues) {
+	for name, value := range defaults.Base {
+		if _, ok := v.Base[name]; ok {
+			continue
+		}
+
+		if v.Base == nil {
+			v.Base = OptionNameToValue{}
+		}
+		v.Base[name] = value
+	}
+
+	for category, values := range defaults.Extensions {
+		for name, value := range values {
+			if _, ok := v.Extensions[category][name]; ok {
+				continue
+			}
+
+			if v.Extensions == nil {
+				v.Extensions = map[string]OptionNameToValue{}
+			}
+			if v.Extensions[category] == nil {
+				v.Extensions[category] = OptionNameToValue{}
+			}
+			v.Extensions[category][name] = value
+		}
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"bufio"
 	"context"
 	"io"
+	"io/fs"
+	"math/rand"
 	"net/http"
+	"reflect"
 	"sync"
 	"text/template"
 	"time"
@@ -17,11 +20,73 @@ import (
 
 type GT struct {
 	Streams
-	Options         *Options
-	FuncMap         template.FuncMap
-	GithubTagLister repos.GithubTagLister
-	once            sync.Once
-	output          *termenv.Output
+	Options *Options
+	FuncMap template.FuncMap
+	// TemplateFS contains the files of the template that is rendered.
+	// If not set the template embedded in the binary is used.
+	// It can also be set from a TemplateSource with UseTemplateSource.
+	TemplateFS fs.FS
+	// AfterRender is called for every rendered file before it is written.
+	// path is the path of the file relative to the project's root.
+	// The returned content is written instead of the rendered one.
+	// If ErrSkipFile is returned the file is not written at all.
+	AfterRender func(path string, content []byte) ([]byte, error)
+	// ErrorFormatter is applied to all errors returned by an option's validator
+	// before they are returned or printed.
+	// If not set the default formatting is used.
+	ErrorFormatter func(optionName string, err error) error
+	// LiteralSubstitutions maps placeholders to their replacements for all template files
+	// with a ".literal" suffix. Those files are not executed as templates (so they can contain
+	// "{{" or binary data), instead all placeholders are replaced literally and the suffix is removed.
+	// The replacements are templates themselves that are executed with the option values.
+	LiteralSubstitutions map[string]string
+	// Clock returns the current time used while rendering (e.g. for ".Meta.Year" or "now").
+	// If not set the real time is used.
+	Clock func() time.Time
+	// Rand is the source for the random functions that can be used in templates (e.g. "randAlphaNum").
+	// If not set sprig's default random functions are used.
+	Rand *rand.Rand
+	// PlaceholderImportPath is an import path (prefix) used in the template's Go files
+	// that is replaced with the project's moduleName in all imports after generation.
+	// This allows the template's code to compile on its own.
+	PlaceholderImportPath string
+	// FileHeader is a template for a header (e.g. a license notice) that is added to all rendered files
+	// as a comment. The comment style is chosen by the file extension ("//" for Go, "#" for shell or YAML)
+	// and files with an unknown style as well as literal files don't get a header.
+	FileHeader string
+	// DataProviders compute additional data that can be used in templates as ".Meta.<name>", e.g. ".Meta.gitSHA".
+	// They are evaluated once per call (e.g. of InitNewProject or GenerateMatrix) before any file is rendered
+	// and an error aborts the generation.
+	// The meta data that is always set (e.g. ".Meta.Year") can't be overridden.
+	DataProviders map[string]func() (interface{}, error)
+	// RecoveryFile is the path the values of an interactive session are saved to after every answer.
+	// If the session is interrupted the next one asks whether to resume from it and then only asks for the missing values.
+	// Recovered values are validated like values in a file and asked again if they are not valid.
+	// The file is removed once all values are loaded. If not set nothing is saved.
+	RecoveryFile string
+	// ForceTidy runs go mod tidy for generated projects even if only the standard library is imported.
+	// By default it's skipped in that case.
+	ForceTidy bool
+	// MaxFileSize is the maximum size in bytes of a rendered file.
+	// The generation is aborted if any file exceeds it. Zero means unlimited.
+	MaxFileSize int
+	// NormalizeEditorConfig enables normalizing all rendered files according to the
+	// .editorconfig in the template's root (indent style, trailing whitespace, final newline).
+	NormalizeEditorConfig bool
+	GithubTagLister       repos.GithubTagLister
+	once                  sync.Once
+	// inputHistory contains the last value entered interactively per option type,
+	// which can be recalled with the recallToken.
+	inputHistory map[reflect.Type]string
+	output       *termenv.Output
+}
+
+func (gt *GT) templateFS() (fs.FS, error) {
+	if gt.TemplateFS != nil {
+		return gt.TemplateFS, nil
+	}
+
+	return EmbeddedTemplateSource{}.TemplateFS()
 }
 
 func (gt *GT) styler() *termenv.Output {
@@ -46,6 +111,9 @@ type Streams struct {
 	Out       io.Writer
 	Err       io.Writer
 	InScanner *bufio.Scanner
+	// RecordInput receives every line that is read from InScanner if set.
+	// The recorded lines can be used as input again to replay the session.
+	RecordInput io.Writer
 }
 
 func New() *GT {
@@ -64,9 +132,18 @@ func New() *GT {
 		return tagStrings, nil
 	})
 
+	options := NewOptions(githubTagLister)
+	if err := options.ValidateDefaults(); err != nil {
+		// panic here since the options are statically defined
+		// and broken defaults are a programming error
+		panic(err)
+	}
+
 	return &GT{
-		Options:         NewOptions(githubTagLister),
+		Options:         options,
 		GithubTagLister: githubTagLister,
 		FuncMap:         sprig.TxtFuncMap(),
+		// the embedded template is expected to fulfill its own .editorconfig
+		NormalizeEditorConfig: true,
 	}
 }
@@ -0,0 +1,50 @@
+package gotemplate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+)
+
+// OutputHash generates the project for the given values into a temporary directory and returns a
+// hash over the paths, permissions and contents of all its files, e.g. to detect if a project would change.
+// Like for Plan the postHooks are executed and imports are rewritten, but git and Go modules are
+// not initialized. The temporary directory is removed afterwards.
+func (gt *GT) OutputHash(values *OptionValues) (string, error) {
+	ctx, err := gt.newRenderContext()
+	if err != nil {
+		return "", err
+	}
+
+	hash, _, err := gt.outputHash(ctx, values)
+	return hash, err
+}
+
+// outputHash computes the hash like OutputHash and additionally returns the paths of all files in lexical order.
+func (gt *GT) outputHash(ctx *renderContext, values *OptionValues) (string, []string, error) {
+	files, err := gt.renderProject(ctx, values)
+	if err != nil {
+		return "", nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	hash := sha256.New()
+	for _, filePath := range paths {
+		file := files[filePath]
+		if _, err := fmt.Fprintf(hash, "file %s %o %d\n", file.path, file.permissions, len(file.content)); err != nil {
+			return "", nil, err
+		}
+
+		if _, err := hash.Write(file.content); err != nil {
+			return "", nil, err
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), paths, nil
+}
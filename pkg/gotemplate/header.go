@@ -0,0 +1,71 @@
+package gotemplate
+
+import (
+	"bytes"
+	"path"
+	"strings"
+)
+
+// headerCommentPrefixes maps file extensions (or names for files without one) to the
+// prefix that is used to comment out each line of the file header.
+//
+//nolint:gochecknoglobals // lookup table for comment styles
+var headerCommentPrefixes = map[string]string{
+	".go":         "//",
+	".proto":      "//",
+	".js":         "//",
+	".ts":         "//",
+	".sh":         "#",
+	".bash":       "#",
+	".py":         "#",
+	".yml":        "#",
+	".yaml":       "#",
+	".toml":       "#",
+	"Makefile":    "#",
+	"Dockerfile":  "#",
+	".dockerfile": "#",
+}
+
+// addFileHeader prepends the rendered header to content as a comment in the style of the file's language.
+// Files with an unknown comment style are returned unchanged.
+// If the file starts with a shebang the header is added after it.
+func addFileHeader(filePath string, content []byte, header string) []byte {
+	prefix, ok := commentPrefix(filePath)
+	if !ok || header == "" {
+		return content
+	}
+
+	var comment bytes.Buffer
+	for _, line := range strings.Split(strings.TrimRight(header, "\n"), "\n") {
+		comment.WriteString(strings.TrimRight(prefix+" "+line, " "))
+		comment.WriteString("\n")
+	}
+	// a blank line separates the header from the content, e.g. so it's not used as package doc in Go
+	comment.WriteString("\n")
+
+	var shebang []byte
+	if bytes.HasPrefix(content, []byte("#!")) {
+		end := bytes.IndexByte(content, '\n')
+		if end < 0 {
+			return append(append(content, '\n'), comment.Bytes()...)
+		}
+		shebang, content = content[:end+1], content[end+1:]
+	}
+
+	result := make([]byte, 0, len(shebang)+comment.Len()+len(content))
+	result = append(result, shebang...)
+	result = append(result, comment.Bytes()...)
+
+	return append(result, content...)
+}
+
+func commentPrefix(filePath string) (string, bool) {
+	name := path.Base(filePath)
+	if prefix, ok := headerCommentPrefixes[path.Ext(name)]; ok {
+		return prefix, true
+	}
+
+	prefix, ok := headerCommentPrefixes[name]
+
+	return prefix, ok
+}
@@ -0,0 +1,130 @@
+package gotemplate
+
+import (
+	"bytes"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// rewriteImports replaces the import path prefix placeholder with moduleName in all
+// imports of the Go files in targetDir.
+// Only files that contain such an import are rewritten.
+func rewriteImports(targetDir, placeholder, moduleName string) error {
+	return filepath.WalkDir(targetDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && d.Name() == gitDir {
+			return fs.SkipDir
+		}
+
+		if d.IsDir() || filepath.Ext(filePath) != ".go" {
+			return nil
+		}
+
+		return rewriteFileImports(filePath, placeholder, moduleName)
+	})
+}
+
+func rewriteFileImports(filePath, placeholder, moduleName string) error {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, filePath, nil, parser.ParseComments)
+	if err != nil {
+		return errors.Wrapf(err, "rewriting imports of %s", filePath)
+	}
+
+	rewritten := false
+	for _, importSpec := range file.Imports {
+		importPath, err := strconv.Unquote(importSpec.Path.Value)
+		if err != nil {
+			return err
+		}
+
+		if importPath != placeholder && !strings.HasPrefix(importPath, placeholder+"/") {
+			continue
+		}
+
+		importSpec.Path.Value = strconv.Quote(moduleName + strings.TrimPrefix(importPath, placeholder))
+		rewritten = true
+	}
+
+	if !rewritten {
+		return nil
+	}
+
+	var buffer bytes.Buffer
+	if err := format.Node(&buffer, fileSet, file); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, buffer.Bytes(), info.Mode().Perm())
+}
+
+// hasExternalImports reports whether any Go file in targetDir imports a package that is
+// neither part of the standard library nor of the module itself.
+// Standard library packages are detected by not having a dot in their first path element.
+// Files that can't be parsed are assumed to have external imports.
+func hasExternalImports(targetDir, moduleName string) (bool, error) {
+	errFound := errors.New("found external import")
+
+	err := filepath.WalkDir(targetDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && d.Name() == gitDir {
+			return fs.SkipDir
+		}
+
+		if d.IsDir() || filepath.Ext(filePath) != ".go" {
+			return nil
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), filePath, nil, parser.ImportsOnly)
+		if err != nil {
+			return errFound
+		}
+
+		for _, importSpec := range file.Imports {
+			importPath, err := strconv.Unquote(importSpec.Path.Value)
+			if err != nil {
+				return errFound
+			}
+
+			if isExternalImport(importPath, moduleName) {
+				return errFound
+			}
+		}
+
+		return nil
+	})
+	if errors.Is(err, errFound) {
+		return true, nil
+	}
+
+	return false, err
+}
+
+func isExternalImport(importPath, moduleName string) bool {
+	if importPath == moduleName || strings.HasPrefix(importPath, moduleName+"/") {
+		return false
+	}
+
+	firstElement, _, _ := strings.Cut(importPath, "/")
+
+	return strings.Contains(firstElement, ".")
+}
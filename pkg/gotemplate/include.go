@@ -0,0 +1,34 @@
+package gotemplate
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	maxIncludeDepth = 10
+	// partialsDir is the directory of the template that contains files which are only meant to be included.
+	// It's not part of the generated project.
+	partialsDir = "_partials"
+)
+
+var ErrMaxIncludeDepth = fmt.Errorf("include depth exceeds maximum of %d", maxIncludeDepth)
+
+// include renders the file name of the template FS with the option values and returns the result.
+// Included files can include other files themselves up to maxIncludeDepth levels, which also
+// stops endless recursion if files include each other.
+// Files that should not be generated themselves belong into partialsDir, e.g. "_partials/license.md".
+func (gt *GT) include(ctx *renderContext, name string, optionValues *OptionValues, depth int) (string, error) {
+	if depth > maxIncludeDepth {
+		return "", errors.Wrap(ErrMaxIncludeDepth, name)
+	}
+
+	fileBytes, err := fs.ReadFile(ctx.templateFS, name)
+	if err != nil {
+		return "", err
+	}
+
+	return gt.executeTemplateStringWithDepth(ctx, string(fileBytes), optionValues, depth)
+}
@@ -0,0 +1,40 @@
+package gotemplate
+
+import (
+	"bytes"
+	"sort"
+)
+
+const literalFileSuffix = ".literal"
+
+// substituteLiterals replaces all placeholders of gt.LiteralSubstitutions in content.
+// Longer placeholders are replaced first, so placeholders that are part of others don't break them.
+func (gt *GT) substituteLiterals(ctx *renderContext, content []byte, optionValues *OptionValues) ([]byte, error) {
+	placeholders := make([]string, 0, len(gt.LiteralSubstitutions))
+	for placeholder := range gt.LiteralSubstitutions {
+		// an empty placeholder would match between all bytes
+		if placeholder == "" {
+			continue
+		}
+		placeholders = append(placeholders, placeholder)
+	}
+
+	sort.Slice(placeholders, func(i, j int) bool {
+		if len(placeholders[i]) != len(placeholders[j]) {
+			return len(placeholders[i]) > len(placeholders[j])
+		}
+
+		return placeholders[i] < placeholders[j]
+	})
+
+	for _, placeholder := range placeholders {
+		replacement, err := gt.executeTemplateString(ctx, gt.LiteralSubstitutions[placeholder], optionValues)
+		if err != nil {
+			return nil, err
+		}
+
+		content = bytes.ReplaceAll(content, []byte(placeholder), []byte(replacement))
+	}
+
+	return content, nil
+}
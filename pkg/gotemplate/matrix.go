@@ -0,0 +1,44 @@
+package gotemplate
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// MatrixResult describes the project that is generated for one combination of option values.
+// It can be encoded as JSON, e.g. to be used as an artifact or matrix in CI.
+type MatrixResult struct {
+	Values *OptionValues `json:"values"`
+	// Hash is the output hash of the project as returned by OutputHash.
+	Hash string `json:"hash"`
+	// Files contains the paths of all files of the generated project relative to its root in lexical order.
+	// Files that are removed by the postHooks are not part of it.
+	Files []string `json:"files"`
+}
+
+// GenerateMatrix generates the project for every combination of option values and returns
+// a result for each of them in the same order.
+// Like for OutputHash the projects are only generated into temporary directories that are removed afterwards.
+func (gt *GT) GenerateMatrix(combinations []*OptionValues) ([]MatrixResult, error) {
+	ctx, err := gt.newRenderContext()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]MatrixResult, 0, len(combinations))
+	for i, values := range combinations {
+		hash, files, err := gt.outputHash(ctx, values)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("combination %d", i))
+		}
+
+		results = append(results, MatrixResult{
+			Values: values,
+			Hash:   hash,
+			Files:  files,
+		})
+	}
+
+	return results, nil
+}
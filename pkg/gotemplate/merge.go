@@ -0,0 +1,143 @@
+package gotemplate
+
+import (
+	"bytes"
+	"strings"
+)
+
+const (
+	conflictMarkerCurrent  = "<<<<<<< current\n"
+	conflictMarkerSplit    = "=======\n"
+	conflictMarkerTemplate = ">>>>>>> template\n"
+)
+
+// ThreeWayMerge merges the changes a user made to a generated file with the changes of a newly
+// rendered version of the same file.
+// original is the content the file was generated with, current the content of the file in the
+// project and rendered the content that is generated right now.
+// Lines changed only on one side are taken from that side. If both sides changed the same lines
+// differently the result contains conflict markers (like git) and hasConflicts is true.
+func ThreeWayMerge(original, current, rendered []byte) (merged []byte, hasConflicts bool) {
+	base, ours, theirs := splitLines(original), splitLines(current), splitLines(rendered)
+	matchOurs, matchTheirs := matchLines(base, ours), matchLines(base, theirs)
+
+	var out bytes.Buffer
+	i, o, t := 0, 0, 0
+	for {
+		// lines that are unchanged on both sides
+		for i < len(base) && matchOurs[i] == o && matchTheirs[i] == t {
+			out.WriteString(base[i])
+			i, o, t = i+1, o+1, t+1
+		}
+
+		if i == len(base) && o == len(ours) && t == len(theirs) {
+			break
+		}
+
+		// find the next line of base that is still present on both sides
+		next := i
+		for next < len(base) && (matchOurs[next] < 0 || matchTheirs[next] < 0) {
+			next++
+		}
+
+		nextOurs, nextTheirs := len(ours), len(theirs)
+		if next < len(base) {
+			nextOurs, nextTheirs = matchOurs[next], matchTheirs[next]
+		}
+
+		baseChunk, oursChunk, theirsChunk := base[i:next], ours[o:nextOurs], theirs[t:nextTheirs]
+		switch {
+		case equalLines(oursChunk, baseChunk), equalLines(oursChunk, theirsChunk):
+			writeLines(&out, theirsChunk, false)
+		case equalLines(theirsChunk, baseChunk):
+			writeLines(&out, oursChunk, false)
+		default:
+			hasConflicts = true
+			out.WriteString(conflictMarkerCurrent)
+			writeLines(&out, oursChunk, true)
+			out.WriteString(conflictMarkerSplit)
+			writeLines(&out, theirsChunk, true)
+			out.WriteString(conflictMarkerTemplate)
+		}
+
+		i, o, t = next, nextOurs, nextTheirs
+	}
+
+	return out.Bytes(), hasConflicts
+}
+
+// splitLines splits content into lines that keep their line endings.
+func splitLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// matchLines returns for every line of a the index of the same line in b
+// according to the longest common subsequence of both, or -1 if the line is not in b.
+func matchLines(a, b []string) []int {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	matches := make([]int, len(a))
+	i, j := 0, 0
+	for i < len(a) {
+		switch {
+		case j < len(b) && a[i] == b[j]:
+			matches[i] = j
+			i, j = i+1, j+1
+		case j < len(b) && lcs[i+1][j] < lcs[i][j+1]:
+			j++
+		default:
+			matches[i] = -1
+			i++
+		}
+	}
+
+	return matches
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// writeLines writes all lines to out.
+// If terminate is set the last line always ends with a newline, e.g. to start conflict markers on a new line.
+func writeLines(out *bytes.Buffer, lines []string, terminate bool) {
+	for _, line := range lines {
+		out.WriteString(line)
+	}
+
+	if terminate && len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+		out.WriteString("\n")
+	}
+}
@@ -0,0 +1,41 @@
+package gotemplate_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/schwarzit/go-template/pkg/gotemplate"
+)
+
+func TestThreeWayMerge(t *testing.T) {
+	original := "# project\n\nSome description.\n\n## Usage\n\nmake run\n"
+
+	t.Run("keeps user changes alongside template changes", func(t *testing.T) {
+		current := "# project\n\nSome description written by the user.\n\n## Usage\n\nmake run\n"
+		rendered := "# project\n\nSome description.\n\n## Usage\n\nmake run\nmake test\n"
+
+		merged, hasConflicts := gotemplate.ThreeWayMerge([]byte(original), []byte(current), []byte(rendered))
+		require.False(t, hasConflicts)
+		require.Equal(t, "# project\n\nSome description written by the user.\n\n## Usage\n\nmake run\nmake test\n", string(merged))
+	})
+
+	t.Run("identical changes don't conflict", func(t *testing.T) {
+		changed := "# project\n\nOther description.\n\n## Usage\n\nmake run\n"
+
+		merged, hasConflicts := gotemplate.ThreeWayMerge([]byte(original), []byte(changed), []byte(changed))
+		require.False(t, hasConflicts)
+		require.Equal(t, changed, string(merged))
+	})
+
+	t.Run("conflicting changes are marked", func(t *testing.T) {
+		current := "# project\n\nUser description.\n\n## Usage\n\nmake run\n"
+		rendered := "# project\n\nTemplate description.\n\n## Usage\n\nmake run\n"
+
+		merged, hasConflicts := gotemplate.ThreeWayMerge([]byte(original), []byte(current), []byte(rendered))
+		require.True(t, hasConflicts)
+		require.Equal(t, "# project\n\n"+
+			"<<<<<<< current\nUser description.\n=======\nTemplate description.\n>>>>>>> template\n"+
+			"\n## Usage\n\nmake run\n", string(merged))
+	})
+}
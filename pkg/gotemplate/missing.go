@@ -0,0 +1,155 @@
+package gotemplate
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// MissingOptionsForTemplates scans all template files (and their paths) for references of option values
+// like ".Base.projectName" or ".Extensions.grpc.base" and returns the ones that are not backed by an option
+// of gt.Options, since they would be rendered as "<no value>".
+// Base options are returned by their name, extension options as "<category>.<option>" and references
+// of a whole category by the category's name.
+// Only references with literal field names are found, e.g. keys used through "index" are not checked.
+func (gt *GT) MissingOptionsForTemplates() ([]string, error) {
+	templateFS, err := gt.templateFS()
+	if err != nil {
+		return nil, err
+	}
+	// the templates are only parsed, so the DataProviders don't need to be evaluated
+	ctx := &renderContext{templateFS: templateFS}
+
+	missing := map[string]struct{}{}
+	err = fs.WalkDir(ctx.templateFS, ".", func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err := gt.collectMissingOptions(ctx, filePath, filePath, missing); err != nil {
+			return err
+		}
+
+		if d.IsDir() || strings.HasSuffix(filePath, literalFileSuffix) {
+			return nil
+		}
+
+		fileBytes, err := fs.ReadFile(ctx.templateFS, filePath)
+		if err != nil {
+			return err
+		}
+
+		return gt.collectMissingOptions(ctx, filePath, string(fileBytes), missing)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// collectMissingOptions parses content as template and adds all option references that are not backed
+// by an option to missing.
+func (gt *GT) collectMissingOptions(ctx *renderContext, name, content string, missing map[string]struct{}) error {
+	tmpl, err := template.New(name).Funcs(gt.funcMap(ctx, NewOptionValues(), 0)).Parse(content)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+
+		walkTemplateNodes(t.Tree.Root, func(fields []string) {
+			if reference, ok := gt.missingOption(fields); ok {
+				missing[reference] = struct{}{}
+			}
+		})
+	}
+
+	return nil
+}
+
+// missingOption checks if the field chain (e.g. ["Base", "projectName"]) references an option
+// that does not exist and returns its name in that case.
+func (gt *GT) missingOption(fields []string) (string, bool) {
+	switch {
+	case len(fields) >= 2 && fields[0] == "Base":
+		if _, ok := gt.Options.lookup(fields[1]); !ok {
+			return fields[1], true
+		}
+	case len(fields) == 2 && fields[0] == "Extensions":
+		for _, category := range gt.Options.Extensions {
+			if category.Name == fields[1] {
+				return "", false
+			}
+		}
+		return fields[1], true
+	case len(fields) >= 3 && fields[0] == "Extensions":
+		name := fmt.Sprintf("%s.%s", fields[1], fields[2])
+		if _, ok := gt.Options.lookup(name); !ok {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// walkTemplateNodes calls visit with the field chain of every field and variable node below node.
+// Fields of the root variable "$" are passed without it.
+func walkTemplateNodes(node parse.Node, visit func(fields []string)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkTemplateNodes(child, visit)
+		}
+	case *parse.ActionNode:
+		walkTemplateNodes(n.Pipe, visit)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			walkTemplateNodes(cmd, visit)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			walkTemplateNodes(arg, visit)
+		}
+	case *parse.IfNode:
+		walkBranchNode(&n.BranchNode, visit)
+	case *parse.RangeNode:
+		walkBranchNode(&n.BranchNode, visit)
+	case *parse.WithNode:
+		walkBranchNode(&n.BranchNode, visit)
+	case *parse.TemplateNode:
+		walkTemplateNodes(n.Pipe, visit)
+	case *parse.ChainNode:
+		walkTemplateNodes(n.Node, visit)
+	case *parse.FieldNode:
+		visit(n.Ident)
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			visit(n.Ident[1:])
+		}
+	}
+}
+
+func walkBranchNode(n *parse.BranchNode, visit func(fields []string)) {
+	walkTemplateNodes(n.Pipe, visit)
+	walkTemplateNodes(n.List, visit)
+	walkTemplateNodes(n.ElseList, visit)
+}
@@ -3,11 +3,14 @@ package gotemplate
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
@@ -16,25 +19,42 @@ import (
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 
-	gotemplate "github.com/schwarzit/go-template"
 	ownexec "github.com/schwarzit/go-template/pkg/exec"
 	"github.com/schwarzit/go-template/pkg/gocli"
 )
 
 const (
-	minGoVersion  = "1.15"
-	permissionRWX = 0755
-	permissionRW  = 0644
+	minGoVersion          = "1.15"
+	minGoToolchainVersion = "1.21"
+	minGoWorkspaceVersion = "1.18"
+	permissionRWX         = 0755
+	permissionRW          = 0644
+	// recallToken entered for an option is replaced with the last value entered for an option of the same type.
+	recallToken = "!!"
 )
 
 var (
-	ErrAlreadyExists         = errors.New("already exists")
-	ErrParameterNotSet       = errors.New("parameter not set")
-	ErrMalformedInput        = errors.New("malformed input")
-	ErrParameterSet          = errors.New("parameter set but has no effect in this context")
-	ErrGoVersionNotSupported = fmt.Errorf("go version is not supported, gt requires at least %s", minGoVersion)
+	ErrAlreadyExists           = errors.New("already exists")
+	ErrParameterNotSet         = errors.New("parameter not set")
+	ErrMalformedInput          = errors.New("malformed input")
+	ErrParameterSet            = errors.New("parameter set but has no effect in this context")
+	ErrGoVersionNotSupported   = fmt.Errorf("go version is not supported, gt requires at least %s", minGoVersion)
+	ErrToolchainNotSupported   = fmt.Errorf("go version does not support toolchains, at least %s is required", minGoToolchainVersion)
+	ErrWorkspaceNotSupported   = fmt.Errorf("go version does not support workspaces, at least %s is required", minGoWorkspaceVersion)
+	ErrSkipFile                = errors.New("skip this file")
+	ErrMissingTools            = errors.New("required tools are missing")
+	ErrAborted                 = errors.New("aborted by user")
+	ErrUnknownOption           = errors.New("unknown option")
+	ErrAssertionFailed         = errors.New("assertion failed")
+	ErrFileTooLarge            = errors.New("rendered file exceeds maximum size")
+	ErrLossyDump               = errors.New("values change when dumped and loaded again")
+	ErrSubmoduleNotInitialized = errors.New("submodule was not initialized")
 
 	minGoVersionSemver = semver.MustParse(minGoVersion) //nolint:gochecknoglobals // parsed semver from const minGoVersion
+	//nolint:gochecknoglobals // parsed semver from const minGoToolchainVersion
+	minGoToolchainVersionSemver = semver.MustParse(minGoToolchainVersion)
+	//nolint:gochecknoglobals // parsed semver from const minGoWorkspaceVersion
+	minGoWorkspaceVersionSemver = semver.MustParse(minGoWorkspaceVersion)
 )
 
 type ErrTypeMismatch struct {
@@ -49,10 +69,28 @@ func (e *ErrTypeMismatch) Error() string {
 type NewRepositoryOptions struct {
 	OutputDir    string
 	OptionValues *OptionValues
+	// ArchiveFile is an optional path to a .tar.gz file.
+	// If set the project is written into this archive instead of OutputDir.
+	ArchiveFile string
+	// IncludeGit defines whether the project should contain an initialized git repository
+	// when written to ArchiveFile. It has no effect otherwise.
+	IncludeGit bool
+	// Confirm defines whether the option values need to be confirmed with ConfirmNewProject
+	// before the project is generated. It should only be set if the values were loaded interactively
+	// to keep support for non-interactive usage.
+	Confirm bool
+	// AssumeYes skips the confirmation of the option values before the project is generated.
+	AssumeYes bool
 }
 
 // Validate validates all properties of NewRepositoryOptions except the ConfigValues, since those are validated by the Load functions.
 func (opts NewRepositoryOptions) Validate() error {
+	if opts.ArchiveFile != "" {
+		if _, err := os.Stat(opts.ArchiveFile); !os.IsNotExist(err) {
+			return errors.Wrapf(ErrAlreadyExists, "archive %s", opts.ArchiveFile)
+		}
+	}
+
 	if opts.OutputDir == "" {
 		return nil
 	}
@@ -71,24 +109,38 @@ func (gt *GT) LoadConfigValuesFromFile(file string) (*OptionValues, error) { //n
 		return nil, err
 	}
 
+	options, globalValues, err := gt.loadOptions()
+	if err != nil {
+		return nil, err
+	}
+
 	var optionValues OptionValues
 
 	if err := yaml.Unmarshal(fileBytes, &optionValues); err != nil {
 		return nil, err
 	}
 
-	for _, option := range gt.Options.Base {
+	// values from the global config are used for everything that is not set explicitly
+	optionValues.setDefaults(globalValues)
+
+	for _, option := range options.Base {
 		val, ok := optionValues.Base[option.Name()]
+		if !ok && option.optional {
+			val, ok = option.Default(&optionValues), true
+		}
 		if !ok || reflect.ValueOf(val).IsZero() {
 			return nil, errors.Wrap(ErrParameterNotSet, option.Name())
 		}
 
-		if err := validateFileOption(option, val, optionValues); err != nil {
+		val = option.Transform(coerceFileValue(&option, val, &optionValues))
+		optionValues.Base[option.Name()] = val
+
+		if err := gt.validateFileOption(option, val, optionValues); err != nil {
 			return nil, err
 		}
 	}
 
-	for _, category := range gt.Options.Extensions {
+	for _, category := range options.Extensions {
 		if optionValues.Extensions == nil {
 			optionValues.Extensions = map[string]OptionNameToValue{}
 		}
@@ -103,93 +155,270 @@ func (gt *GT) LoadConfigValuesFromFile(file string) (*OptionValues, error) { //n
 				continue
 			}
 
-			if err := validateFileOption(option, val, optionValues); err != nil {
+			val = option.Transform(coerceFileValue(&option, val, &optionValues))
+			optionValues.Extensions[category.Name][option.Name()] = val
+
+			if err := gt.validateFileOption(option, val, optionValues); err != nil {
 				return nil, err
 			}
 		}
 	}
 
+	if err := gt.ValidateOptionValues(&optionValues); err != nil {
+		return nil, err
+	}
+
 	return &optionValues, nil
 }
 
-func validateFileOption(option Option, value interface{}, optionValues OptionValues) error {
-	valType := reflect.TypeOf(value)
-	defaultVal := option.Default(&optionValues)
-	defaultType := reflect.TypeOf(defaultVal)
-	if valType != defaultType {
-		return &ErrTypeMismatch{
-			Expected: defaultType.Name(),
-			Actual:   valType.Name(),
-		}
+// coerceFileValue converts boolean-like strings (e.g. "yes" or "0") to bools if the option is a bool option.
+// All other values are returned as they are, so strings that are no clear boolean still fail the type check.
+func coerceFileValue(option *Option, value interface{}, optionValues *OptionValues) interface{} {
+	s, ok := value.(string)
+	if !ok {
+		return value
 	}
 
-	if err := option.Validate(value); err != nil {
-		return errors.Wrap(ErrMalformedInput, fmt.Sprintf("%s: %s", option.Name(), err.Error()))
+	if _, isBool := option.Default(optionValues).(bool); !isBool {
+		return value
+	}
+
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "yes", "y", "on", "1":
+		return true
+	case "false", "no", "n", "off", "0":
+		return false
+	default:
+		return value
+	}
+}
+
+// validateFileOption validates a value loaded from a file with validateValue and additionally
+// checks that it has an effect.
+func (gt *GT) validateFileOption(option Option, value interface{}, optionValues OptionValues) error {
+	if err := validateValue(&option, option.Name(), value, &optionValues, gt.ErrorFormatter); err != nil {
+		return err
 	}
 
 	// if it is set to sth else than default with shouldDisplay returning false it means the parameters does not have any effect
-	if value != defaultVal && !option.ShouldDisplay(&optionValues) {
+	if value != option.Default(&optionValues) && !option.ShouldDisplay(&optionValues) {
 		return errors.Wrap(ErrParameterSet, option.Name())
 	}
 
 	return nil
 }
 
-func (gt *GT) LoadConfigValuesInteractively() (*OptionValues, error) {
+func (gt *GT) LoadConfigValuesInteractively() (*OptionValues, error) { //nolint:cyclop // todo refactor
+	options, _, err := gt.loadOptions()
+	if err != nil {
+		return nil, err
+	}
+
 	gt.printBanner()
 	optionValues := NewOptionValues()
 
-	for i := range gt.Options.Base {
-		val := gt.loadOptionValueInteractively(&gt.Options.Base[i], optionValues)
+	recovered, err := gt.loadRecoveredValues()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range options.Base {
+		name := options.Base[i].Name()
+		if val, ok := gt.recoveredValue(&options.Base[i], recovered.Base, optionValues); ok {
+			optionValues.Base[name] = val
+			continue
+		}
+
+		val, err := gt.loadOptionValueInteractively(&options.Base[i], optionValues)
+		if err != nil {
+			return nil, err
+		}
 
 		if val == nil {
 			continue
 		}
 
-		optionValues.Base[gt.Options.Base[i].Name()] = val
+		optionValues.Base[name] = val
+		gt.saveRecoveredValues(optionValues)
 	}
 
 	gt.printProgressf("\nYou now have the option to enable additional extensions (organized in different categories)...\n\n")
-	for _, category := range gt.Options.Extensions {
-		gt.printCategory(category.Name)
+	for _, category := range sortCategories(options.Extensions, optionValues) {
 		optionValues.Extensions[category.Name] = OptionNameToValue{}
 
+		if !category.shouldDisplay(optionValues) {
+			for i := range category.Options {
+				optionValues.Extensions[category.Name][category.Options[i].Name()] = category.Options[i].Default(optionValues)
+			}
+
+			continue
+		}
+
+		gt.printCategory(category.Name)
+
 		for i := range category.Options {
-			val := gt.loadOptionValueInteractively(&category.Options[i], optionValues)
+			name := category.Options[i].Name()
+			if val, ok := gt.recoveredValue(&category.Options[i], recovered.Extensions[category.Name], optionValues); ok {
+				optionValues.Extensions[category.Name][name] = val
+				continue
+			}
+
+			val, err := gt.loadOptionValueInteractively(&category.Options[i], optionValues)
+			if err != nil {
+				return nil, err
+			}
 
 			if val == nil {
 				continue
 			}
 
-			optionValues.Extensions[category.Name][category.Options[i].Name()] = val
+			optionValues.Extensions[category.Name][name] = val
+			gt.saveRecoveredValues(optionValues)
 		}
 	}
 
+	if err := gt.ValidateOptionValues(optionValues); err != nil {
+		return nil, err
+	}
+
+	if err := gt.removeRecoveredValues(); err != nil {
+		return nil, err
+	}
+
 	return optionValues, nil
 }
 
-func (gt *GT) loadOptionValueInteractively(option *Option, optionValues *OptionValues) interface{} {
+// sortCategories returns a copy of categories sorted by their order.
+// The order is evaluated once with the values that are known at this point.
+func sortCategories(categories []Category, optionValues *OptionValues) []Category {
+	orders := make(map[string]int, len(categories))
+	for i := range categories {
+		orders[categories[i].Name] = categories[i].order(optionValues)
+	}
+
+	sorted := make([]Category, len(categories))
+	copy(sorted, categories)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return orders[sorted[i].Name] < orders[sorted[j].Name]
+	})
+
+	return sorted
+}
+
+// loadOptionValueInteractively reads the option's value until a valid one is entered.
+// Errors reading the input are returned, e.g. io.EOF if it was closed.
+func (gt *GT) loadOptionValueInteractively(option *Option, optionValues *OptionValues) (interface{}, error) {
 	if !option.ShouldDisplay(optionValues) {
-		return option.Default(optionValues)
+		return option.Default(optionValues), nil
 	}
 
 	val, err := gt.readOptionValue(option, optionValues)
-	for err != nil {
+	for errors.Is(err, ErrParameterNotSet) {
 		gt.printWarningf(err.Error())
 		val, err = gt.readOptionValue(option, optionValues)
 	}
 
-	return val
+	return val, err
+}
+
+// ConfirmNewProject prints all option values and asks the user whether the project should be generated with them.
+// If the user does not confirm ErrAborted is returned.
+// If opts.AssumeYes is set the values are not printed and no confirmation is needed.
+func (gt *GT) ConfirmNewProject(opts *NewRepositoryOptions) error {
+	if opts.AssumeYes {
+		return nil
+	}
+
+	gt.printProgressf("The project will be generated with the following values:\n")
+	if err := DumpOptionValues(gt.Out, opts.OptionValues); err != nil {
+		return err
+	}
+
+	gt.printf("\nProceed? [y/N] ")
+	s, err := gt.readStdin()
+	if err != nil {
+		return err
+	}
+	gt.printf("\n")
+
+	switch strings.ToLower(s) {
+	case "y", "yes":
+		return nil
+	default:
+		return ErrAborted
+	}
+}
+
+// GenerationResult contains information about a generated project.
+type GenerationResult struct {
+	// RemovedFiles contains the paths of all files (relative to the project's root)
+	// that were removed by the postHooks of unused integrations.
+	RemovedFiles []string
+}
+
+// InitNewProject generates the project with opts.OptionValues.
+// If opts.Confirm is set the values are confirmed first and ErrAborted is returned without generating anything
+// if the user does not confirm them.
+func (gt *GT) InitNewProject(opts *NewRepositoryOptions) (*GenerationResult, error) {
+	if opts.Confirm {
+		if err := gt.ConfirmNewProject(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.ArchiveFile != "" {
+		return gt.initNewProjectArchive(opts)
+	}
+
+	return gt.initNewProject(opts.OutputDir, opts.OptionValues, true)
 }
 
-func (gt *GT) InitNewProject(opts *NewRepositoryOptions) (err error) { //nolint:cyclop // todo refactor
+// initNewProjectArchive generates the project in a temporary directory
+// and writes it to the configured archive file afterwards.
+func (gt *GT) initNewProjectArchive(opts *NewRepositoryOptions) (*GenerationResult, error) {
+	tmpDir, err := os.MkdirTemp("", "gt-")
+	if err != nil {
+		return nil, err
+	}
+	// ignore error since it's only a temp dir
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	result, err := gt.initNewProject(tmpDir, opts.OptionValues, opts.IncludeGit)
+	if err != nil {
+		return nil, err
+	}
+
+	gt.printProgressf("Writing archive %s...", opts.ArchiveFile)
+	targetDir := path.Join(tmpDir, opts.OptionValues.Base["projectSlug"].(string))
+
+	if err := writeArchive(targetDir, opts.ArchiveFile, opts.IncludeGit); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (gt *GT) initNewProject(
+	outputDir string,
+	optionValues *OptionValues,
+	initGit bool,
+) (result *GenerationResult, err error) {
 	gt.printProgressf("Generating repo folder...")
 
-	targetDir := path.Join(opts.OutputDir, opts.OptionValues.Base["projectSlug"].(string))
+	targetDir := path.Join(outputDir, optionValues.Base["projectSlug"].(string))
 	gt.printProgressf("Writing to %s...", targetDir)
 
 	if _, err := os.Stat(targetDir); !os.IsNotExist(err) {
-		return errors.Wrapf(ErrAlreadyExists, "directory %s", targetDir)
+		return nil, errors.Wrapf(ErrAlreadyExists, "directory %s", targetDir)
+	}
+
+	if err := preHook(gt.Options, optionValues); err != nil {
+		return nil, err
+	}
+
+	ctx, err := gt.newRenderContext()
+	if err != nil {
+		return nil, err
 	}
 
 	defer func() {
@@ -198,74 +427,56 @@ func (gt *GT) InitNewProject(opts *NewRepositoryOptions) (err error) { //nolint:
 			_ = os.RemoveAll(targetDir)
 		}
 	}()
-	err = fs.WalkDir(gotemplate.FS, gotemplate.Key, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		pathToWrite, err := gt.executeTemplateString(path, opts.OptionValues)
-		if err != nil {
-			return err
-		}
-
-		pathToWrite = strings.ReplaceAll(pathToWrite, gotemplate.Key, targetDir)
-		if d.IsDir() {
-			return os.MkdirAll(pathToWrite, permissionRWX)
-		}
+	gt.printProgressf("Rendering files and removing obsolete files of unused integrations...")
+	if gt.PlaceholderImportPath != "" {
+		gt.printProgressf("Rewriting imports of %s...", gt.PlaceholderImportPath)
+	}
 
-		fileBytes, err := fs.ReadFile(gotemplate.FS, path)
-		if err != nil {
-			return err
-		}
+	removedFiles, err := gt.writeProject(ctx, targetDir, optionValues, func(renderedFile) {})
+	if err != nil {
+		return nil, err
+	}
+	gt.printRemovedFiles(removedFiles)
 
-		data, err := gt.executeTemplateString(string(fileBytes), opts.OptionValues)
-		if err != nil {
-			return err
-		}
+	gt.printProgressf("Initializing git and Go modules...")
+	gt.initRepo(targetDir, optionValues, initGit)
 
-		filePermissions := fs.FileMode(permissionRW)
-		// files that contain a shebang should be executable
-		if strings.HasPrefix(strings.TrimSpace(data), "#!") {
-			filePermissions = permissionRWX
-		}
+	return &GenerationResult{RemovedFiles: removedFiles}, nil
+}
 
-		return os.WriteFile(pathToWrite, []byte(data), filePermissions)
-	})
+// SmokeTest loads the values from configFile and generates the project into a temporary
+// directory that is removed afterwards.
+// This can be used in CI to check that a config is valid and the template can be rendered with it.
+// The first error that occurs is returned.
+func (gt *GT) SmokeTest(configFile string) error {
+	optionValues, err := gt.LoadConfigValuesFromFile(configFile)
 	if err != nil {
 		return err
 	}
 
-	gt.printProgressf("Removing obsolete files of unused integrations...")
-	if err := postHook(gt.Options, opts.OptionValues, targetDir); err != nil {
+	tmpDir, err := os.MkdirTemp("", "gt-smoke-test-")
+	if err != nil {
 		return err
 	}
+	// ignore error since it's only a temp dir
+	defer func() { _ = os.RemoveAll(tmpDir) }()
 
-	gt.printProgressf("Initializing git and Go modules...")
-	gt.initRepo(targetDir, opts.OptionValues.Base["moduleName"].(string))
+	_, err = gt.InitNewProject(&NewRepositoryOptions{
+		OutputDir:    tmpDir,
+		OptionValues: optionValues,
+	})
 
-	return nil
+	return err
 }
 
-func (gt *GT) initRepo(targetDir, moduleName string) {
-	commandGroups := []ownexec.CommandGroup{
-		{
-			Commands: []*exec.Cmd{
-				exec.Command("git", "init"),
-			},
-			TargetDir: targetDir,
-		},
-		{
-			PreRun: checkGoVersion,
-			Commands: []*exec.Cmd{
-				exec.Command("go", "mod", "init", moduleName),
-				exec.Command("go", "mod", "tidy"),
-			},
-			TargetDir: targetDir,
-		},
+func (gt *GT) initRepo(targetDir string, optionValues *OptionValues, initGit bool) {
+	tidy := gt.needsTidy(targetDir, optionValues.Base["moduleName"].(string))
+	if !tidy {
+		gt.printProgressf("Skipping go mod tidy since only the standard library is used...")
 	}
 
 	failedCGs := 0
-	for _, cg := range commandGroups {
+	for _, cg := range initCommandGroups(targetDir, optionValues, initGit, tidy) {
 		if err := cg.Run(); err != nil {
 			gt.printWarningf(err.Error())
 			failedCGs++
@@ -277,6 +488,62 @@ func (gt *GT) initRepo(targetDir, moduleName string) {
 	}
 }
 
+// initCommandGroups returns the commands that initialize git and Go modules in targetDir.
+func initCommandGroups(targetDir string, optionValues *OptionValues, initGit, tidy bool) []ownexec.CommandGroup {
+	moduleName := optionValues.Base["moduleName"].(string)
+
+	var commandGroups []ownexec.CommandGroup
+	if initGit {
+		commandGroups = append(commandGroups, ownexec.CommandGroup{
+			Commands: []*exec.Cmd{
+				exec.Command("git", "init"),
+			},
+			TargetDir: targetDir,
+		})
+	}
+
+	goModCommands := []*exec.Cmd{
+		exec.Command("go", "mod", "init", moduleName),
+	}
+	if tidy {
+		goModCommands = append(goModCommands, exec.Command("go", "mod", "tidy"))
+	}
+
+	commandGroups = append(commandGroups, ownexec.CommandGroup{
+		PreRun:    checkGoVersion,
+		Commands:  goModCommands,
+		TargetDir: targetDir,
+	})
+
+	if toolchain, _ := optionValues.Extensions["go"]["toolchain"].(string); toolchain != "" {
+		commandGroups = append(commandGroups, ownexec.CommandGroup{
+			PreRun: checkGoToolchainVersion,
+			Commands: []*exec.Cmd{
+				exec.Command("go", "mod", "edit", "-toolchain="+toolchain),
+			},
+			TargetDir: targetDir,
+		})
+	}
+
+	return append(commandGroups, workspaceCommandGroups(targetDir, moduleName, optionValues)...)
+}
+
+// needsTidy reports whether go mod tidy needs to be run, which is the case if any
+// external packages are imported or gt.ForceTidy is set.
+func (gt *GT) needsTidy(targetDir, moduleName string) bool {
+	if gt.ForceTidy {
+		return true
+	}
+
+	external, err := hasExternalImports(targetDir, moduleName)
+	if err != nil {
+		// rather run tidy unnecessarily than skip it
+		return true
+	}
+
+	return external
+}
+
 func checkGoVersion() error {
 	goSemver, err := gocli.Semver()
 	if err != nil {
@@ -290,11 +557,144 @@ func checkGoVersion() error {
 	return nil
 }
 
-func postHook(options *Options, optionValues *OptionValues, targetDir string) error {
+func checkGoToolchainVersion() error {
+	goSemver, err := gocli.Semver()
+	if err != nil {
+		return err
+	}
+
+	if goSemver.LessThan(minGoToolchainVersionSemver) {
+		return errors.Wrap(ErrToolchainNotSupported, goSemver.String())
+	}
+
+	return nil
+}
+
+// workspaceCommandGroups returns the commands to initialize all submodules and a go.work using them.
+// If no submodules are configured no commands are returned.
+func workspaceCommandGroups(targetDir, moduleName string, optionValues *OptionValues) []ownexec.CommandGroup {
+	submodules, _ := optionValues.Extensions["go"]["submodules"].(string)
+	if submodules == "" {
+		return nil
+	}
+
+	workUse := []string{"work", "init", "."}
+	var (
+		commandGroups []ownexec.CommandGroup
+		submoduleDirs []string
+	)
+	for _, submodule := range strings.Split(submodules, ",") {
+		submodule = path.Clean(submodule)
+		submoduleDir := path.Join(targetDir, submodule)
+		submoduleDirs = append(submoduleDirs, submoduleDir)
+		commandGroups = append(commandGroups, ownexec.CommandGroup{
+			PreRun: func() error {
+				if err := checkGoWorkspaceVersion(); err != nil {
+					return err
+				}
+				return os.MkdirAll(submoduleDir, permissionRWX)
+			},
+			Commands: []*exec.Cmd{
+				exec.Command("go", "mod", "init", path.Join(moduleName, submodule)),
+			},
+			TargetDir: submoduleDir,
+		})
+
+		workUse = append(workUse, "./"+submodule)
+	}
+
+	return append(commandGroups, ownexec.CommandGroup{
+		PreRun: func() error {
+			if err := checkGoWorkspaceVersion(); err != nil {
+				return err
+			}
+			// the workspace can't use submodules whose initialization failed
+			for _, submoduleDir := range submoduleDirs {
+				if _, err := os.Stat(path.Join(submoduleDir, "go.mod")); err != nil {
+					return errors.Wrap(ErrSubmoduleNotInitialized, submoduleDir)
+				}
+			}
+			return nil
+		},
+		Commands: []*exec.Cmd{
+			exec.Command("go", workUse...),
+		},
+		TargetDir: targetDir,
+	})
+}
+
+func checkGoWorkspaceVersion() error {
+	goSemver, err := gocli.Semver()
+	if err != nil {
+		return err
+	}
+
+	if goSemver.LessThan(minGoWorkspaceVersionSemver) {
+		return errors.Wrap(ErrWorkspaceNotSupported, goSemver.String())
+	}
+
+	return nil
+}
+
+// preHook checks that all tools required by enabled options are available.
+func preHook(options *Options, optionValues *OptionValues) error {
+	var missing []string
+	check := func(option *Option, value interface{}) {
+		for _, tool := range option.MissingTools(value) {
+			missing = append(missing, fmt.Sprintf("%s (needed by %s)", tool, option.Name()))
+		}
+	}
+
+	for i := range options.Base {
+		check(&options.Base[i], optionValues.Base[options.Base[i].Name()])
+	}
+
+	for _, category := range options.Extensions {
+		for i := range category.Options {
+			check(&category.Options[i], optionValues.Extensions[category.Name][category.Options[i].Name()])
+		}
+	}
+
+	if len(missing) > 0 {
+		return errors.Wrap(ErrMissingTools, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// postHook runs the postHooks of all options and returns the files that were removed by them.
+func postHook(options *Options, optionValues *OptionValues, targetDir string) ([]string, error) {
+	filesBefore, err := listFiles(targetDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := runPostHooks(options, optionValues, targetDir); err != nil {
+		return nil, err
+	}
+
+	filesAfter, err := listFiles(targetDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var removedFiles []string
+	for file := range filesBefore {
+		if _, ok := filesAfter[file]; !ok {
+			removedFiles = append(removedFiles, file)
+		}
+	}
+
+	sort.Strings(removedFiles)
+
+	return removedFiles, nil
+}
+
+func runPostHooks(options *Options, optionValues *OptionValues, targetDir string) error {
 	for _, option := range options.Base {
 		optionValue, ok := optionValues.Base[option.Name()]
 		if !ok {
-			return nil
+			continue
 		}
 
 		if err := option.PostHook(optionValue, optionValues, targetDir); err != nil {
@@ -306,7 +706,7 @@ func postHook(options *Options, optionValues *OptionValues, targetDir string) er
 		for _, option := range category.Options {
 			optionValue, ok := optionValues.Extensions[category.Name][option.Name()]
 			if !ok {
-				return nil
+				continue
 			}
 
 			if err := option.PostHook(optionValue, optionValues, targetDir); err != nil {
@@ -318,70 +718,176 @@ func postHook(options *Options, optionValues *OptionValues, targetDir string) er
 	return nil
 }
 
+// listFiles returns the paths of all files in dir relative to dir.
+func listFiles(dir string) (map[string]struct{}, error) {
+	files := map[string]struct{}{}
+	err := filepath.WalkDir(dir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+
+		files[filepath.ToSlash(relativePath)] = struct{}{}
+
+		return nil
+	})
+
+	return files, err
+}
+
 // readOptionValue reads a value for an option from the cli.
+// If the default value is not valid it can't be accepted and a value needs to be entered explicitly.
 func (gt *GT) readOptionValue(opt *Option, optionValues *OptionValues) (interface{}, error) {
+	defaultVal := opt.Default(optionValues)
+	defaultErr := gt.validateOptionValue(opt, defaultVal, optionValues)
+	if defaultErr != nil {
+		gt.printWarningf("The default value is not valid, please enter a value: %s", defaultErr.Error())
+	}
+
 	gt.printOption(opt, optionValues)
 	defer fmt.Fprintln(gt.Out)
 
-	s, err := gt.readStdin()
-	if err != nil {
-		return nil, err
-	}
-
-	defaultVal := opt.Default(optionValues)
+	var (
+		returnVal interface{}
+		s         string
+		err       error
+	)
+	for {
+		s, err = gt.readStdin()
+		if err != nil {
+			return nil, err
+		}
 
-	var returnVal interface{}
-
-	// TODO: cleanup somehow
-	if s == "" {
-		returnVal = defaultVal
-	} else {
-		switch defaultVal.(type) {
-		case string:
-			returnVal = s
-		case bool:
-			boolVal, err := strconv.ParseBool(s)
-			if err != nil {
-				return nil, err
+		if s == recallToken {
+			previous, ok := gt.inputHistory[reflect.TypeOf(defaultVal)]
+			if !ok {
+				gt.printWarningf("No previous value to recall, please enter a value:")
+				gt.printOptionInput(opt, optionValues)
+				continue
 			}
-			returnVal = boolVal
-		case int:
-			intVal, err := strconv.Atoi(s)
-			if err != nil {
-				return nil, err
+			s = previous
+		}
+
+		if s == "" {
+			if defaultErr != nil {
+				return nil, errors.Wrap(ErrParameterNotSet, opt.Name())
 			}
-			returnVal = intVal
-		default:
-			panic("unsupported type")
+			returnVal = defaultVal
+			break
 		}
+
+		returnVal, err = parseInput(s, defaultVal)
+		if err == nil {
+			break
+		}
+
+		// only the input is read again since the rest of the prompt is still valid
+		gt.printWarningf("%s, please try again:", err.Error())
+		gt.printOptionInput(opt, optionValues)
 	}
 
-	if err := opt.Validate(returnVal); err != nil {
+	returnVal = opt.Transform(returnVal)
+	if err := gt.validateOptionValue(opt, returnVal, optionValues); err != nil {
 		gt.printf("\n")
 		gt.printWarningf("Validation failed: %s", err.Error())
 		return gt.readOptionValue(opt, optionValues)
 	}
 
+	if s != "" {
+		gt.rememberInput(s, defaultVal)
+	}
+
 	return returnVal, nil
 }
 
+// parseInput parses the input s to the type of the option's default value.
+func parseInput(s string, defaultVal interface{}) (interface{}, error) {
+	switch defaultVal.(type) {
+	case string:
+		return s, nil
+	case bool:
+		boolVal, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("expected a boolean (true or false) but got %q", s)
+		}
+		return boolVal, nil
+	case int:
+		intVal, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("expected an integer but got %q", s)
+		}
+		return intVal, nil
+	default:
+		panic("unsupported type")
+	}
+}
+
+// rememberInput stores s as the last input for options of the type of defaultVal, so it can be recalled.
+func (gt *GT) rememberInput(s string, defaultVal interface{}) {
+	if gt.inputHistory == nil {
+		gt.inputHistory = map[reflect.Type]string{}
+	}
+
+	gt.inputHistory[reflect.TypeOf(defaultVal)] = s
+}
+
+// validateOptionValue validates the value with the option's validator and allowed values.
+// Errors are formatted with the ErrorFormatter if it's set.
+func (gt *GT) validateOptionValue(opt *Option, value interface{}, optionValues *OptionValues) error {
+	err := opt.Validate(value)
+	if err == nil {
+		err = opt.ValidateAllowed(value, optionValues)
+	}
+
+	if err != nil && gt.ErrorFormatter != nil {
+		return gt.ErrorFormatter(opt.Name(), err)
+	}
+
+	return err
+}
+
+// readStdin reads the next line from gt.InScanner.
+// io.EOF is returned if the input is closed, so callers don't wait for input that never comes.
 func (gt *GT) readStdin() (string, error) {
 	if ok := gt.InScanner.Scan(); !ok {
-		return "", gt.InScanner.Err()
+		if err := gt.InScanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+
+	if gt.RecordInput != nil {
+		if _, err := fmt.Fprintln(gt.RecordInput, gt.InScanner.Text()); err != nil {
+			return "", err
+		}
 	}
 
 	return strings.TrimSpace(gt.InScanner.Text()), nil
 }
 
 // executeTemplateString executes the template in input str with the default p.FuncMap and valueMap as data.
-func (gt *GT) executeTemplateString(str string, optionValues *OptionValues) (string, error) {
-	tmpl, err := template.New("").Funcs(gt.FuncMap).Parse(str)
+func (gt *GT) executeTemplateString(ctx *renderContext, str string, optionValues *OptionValues) (string, error) {
+	return gt.executeTemplateStringWithDepth(ctx, str, optionValues, 0)
+}
+
+// executeTemplateStringWithDepth executes the template like executeTemplateString.
+// depth is the number of nested includes the template is rendered in.
+func (gt *GT) executeTemplateStringWithDepth(ctx *renderContext, str string, optionValues *OptionValues, depth int) (string, error) {
+	tmpl, err := template.New("").Funcs(gt.funcMap(ctx, optionValues, depth)).Parse(str)
 	if err != nil {
 		return "", err
 	}
 
 	var buffer bytes.Buffer
-	if err := tmpl.Execute(&buffer, optionValues); err != nil {
+	if err := tmpl.Execute(&buffer, gt.templateData(optionValues, ctx.providedData)); err != nil {
 		return "", err
 	}
 
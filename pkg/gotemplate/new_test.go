@@ -1,21 +1,34 @@
 package gotemplate_test
 
 import (
+	"archive/tar"
 	"bufio"
 	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"io/fs"
+	"math/rand"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"testing/iotest"
+	"time"
 
+	"github.com/Masterminds/semver/v3"
+	"github.com/Masterminds/sprig/v3"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 
+	"github.com/schwarzit/go-template/pkg/gocli"
 	"github.com/schwarzit/go-template/pkg/gotemplate"
+	"github.com/schwarzit/go-template/pkg/repos"
 )
 
 var (
@@ -27,6 +40,22 @@ const (
 	optionName          = "someOption"
 )
 
+func TestMain(m *testing.M) {
+	// isolate the tests from the global config of the machine they run on
+	configDir, err := os.MkdirTemp("", "gt-config-")
+	if err != nil {
+		panic(err)
+	}
+
+	if err := os.Setenv(gotemplate.GlobalConfigDirEnv, configDir); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	_ = os.RemoveAll(configDir)
+	os.Exit(code)
+}
+
 func TestNewRepositoryOptions_Validate(t *testing.T) {
 	t.Run("OutputDir does not exist", func(t *testing.T) {
 		opts := gotemplate.NewRepositoryOptions{
@@ -206,6 +235,62 @@ base:
 		require.ErrorAs(t, err, &errTypeMismatch)
 	})
 
+	t.Run("coerces boolean-like strings for bool options", func(t *testing.T) {
+		gt.Options = &gotemplate.Options{
+			Extensions: []gotemplate.Category{
+				{
+					Name: "ci",
+					Options: []gotemplate.Option{
+						gotemplate.NewOption("enableCI", "description", gotemplate.StaticValue(false)),
+						gotemplate.NewOption("enableCD", "description", gotemplate.StaticValue(true)),
+					},
+				},
+			},
+		}
+
+		optionValues, err := loadValueFromTestFile(t, &gt, `---
+extensions:
+    ci:
+        enableCI: "yes"
+        enableCD: "0"
+`)
+		require.NoError(t, err)
+		require.Equal(t, true, optionValues.Extensions["ci"]["enableCI"])
+		require.Equal(t, false, optionValues.Extensions["ci"]["enableCD"])
+
+		_, err = loadValueFromTestFile(t, &gt, `---
+extensions:
+    ci:
+        enableCI: "maybe"
+`)
+		var errTypeMismatch *gotemplate.ErrTypeMismatch
+		require.ErrorAs(t, err, &errTypeMismatch)
+	})
+
+	t.Run("transforms values before validation", func(t *testing.T) {
+		gt.Options = &gotemplate.Options{
+			Base: []gotemplate.Option{
+				gotemplate.NewOption("authorEmail", "description", gotemplate.StaticValue("marty@future.back"),
+					gotemplate.WithValidator(gotemplate.EmailValidator()),
+					gotemplate.WithTransform(gotemplate.NormalizeEmail),
+				),
+			},
+		}
+
+		optionValues, err := loadValueFromTestFile(t, &gt, `---
+base:
+    authorEmail: Marty@Future.Back
+`)
+		require.NoError(t, err)
+		require.Equal(t, "Marty@future.back", optionValues.Base["authorEmail"])
+
+		_, err = loadValueFromTestFile(t, &gt, `---
+base:
+    authorEmail: not an email
+`)
+		require.ErrorIs(t, err, gotemplate.ErrMalformedInput)
+	})
+
 	t.Run("error if option is set but shouldDisplay returns false", func(t *testing.T) {
 		gt.Options = &gotemplate.Options{
 			Extensions: []gotemplate.Category{
@@ -256,13 +341,394 @@ extensions:
 	})
 }
 
-func loadValueFromTestFile(t *testing.T, gt *gotemplate.GT, contents string) (*gotemplate.OptionValues, error) {
-	dir := t.TempDir()
-	testFile := path.Join(dir, "test.yml")
-	err := os.WriteFile(testFile, []byte(contents), os.ModePerm)
+func TestGT_LoadConfigValuesFromFile_Anchors(t *testing.T) {
+	gt := gotemplate.GT{
+		Options: &gotemplate.Options{
+			Base: []gotemplate.Option{
+				gotemplate.NewOption("author", "description", gotemplate.StaticValue("default")),
+				gotemplate.NewOption("codeowner", "description", gotemplate.StaticValue("default")),
+			},
+		},
+	}
+
+	optionValues, err := loadValueFromTestFile(t, &gt, `---
+base:
+    author: &author Marty Mc Fly
+    codeowner: *author
+`)
+
+	require.NoError(t, err)
+	require.Equal(t, gotemplate.OptionNameToValue{
+		"author":    "Marty Mc Fly",
+		"codeowner": "Marty Mc Fly",
+	}, optionValues.Base)
+}
+
+func TestGT_LoadConfigValuesFromFile_Layout(t *testing.T) {
+	gt := gotemplate.GT{
+		Options: gotemplate.NewOptions(repos.GithubTagListerFunc(func(context.Context, string, string) ([]string, error) {
+			return []string{"v1.48.0"}, nil
+		})),
+		FuncMap: sprig.TxtFuncMap(),
+	}
+
+	testValuesBytes, err := os.ReadFile("./testdata/values.yml")
+	require.NoError(t, err)
+
+	t.Run("falls back to the standard layout for files without layout", func(t *testing.T) {
+		withoutLayout := strings.ReplaceAll(string(testValuesBytes), "  layout: standard\n", "")
+		require.NotContains(t, withoutLayout, "layout")
+
+		optionValues, err := loadValueFromTestFile(t, &gt, withoutLayout)
+		require.NoError(t, err)
+		require.Equal(t, "standard", optionValues.Base["layout"])
+	})
+
+	t.Run("error for the flat layout with grpc", func(t *testing.T) {
+		flatWithGRPC := strings.ReplaceAll(string(testValuesBytes), "layout: standard", "layout: flat")
+
+		_, err := loadValueFromTestFile(t, &gt, flatWithGRPC)
+		require.ErrorIs(t, err, gotemplate.ErrAssertionFailed)
+	})
+}
+
+func TestGT_GlobalConfig(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv(gotemplate.GlobalConfigDirEnv, configDir)
+
+	err := os.WriteFile(path.Join(configDir, "config.yml"), []byte(`---
+base:
+    moduleName: github.com/org/project
+extensions:
+    openSource:
+        author: Some Org
+`), os.ModePerm)
+	require.NoError(t, err)
+
+	newGT := func() *gotemplate.GT {
+		return &gotemplate.GT{
+			Streams: gotemplate.Streams{Out: &bytes.Buffer{}, Err: &bytes.Buffer{}},
+			Options: &gotemplate.Options{
+				Base: []gotemplate.Option{
+					gotemplate.NewOption("projectName", "description", gotemplate.StaticValue("project")),
+					gotemplate.NewOption("moduleName", "description", gotemplate.StaticValue("github.com/user/project")),
+				},
+				Extensions: []gotemplate.Category{
+					{
+						Name: "openSource",
+						Options: []gotemplate.Option{
+							gotemplate.NewOption("author", "description", gotemplate.StaticValue("Marty Mc Fly")),
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("global values are used for unset values in files", func(t *testing.T) {
+		optionValues, err := loadValueFromTestFile(t, newGT(), `---
+base:
+    projectName: someProject
+`)
+		require.NoError(t, err)
+		require.Equal(t, &gotemplate.OptionValues{
+			Base: gotemplate.OptionNameToValue{
+				"projectName": "someProject",
+				"moduleName":  "github.com/org/project",
+			},
+			Extensions: map[string]gotemplate.OptionNameToValue{
+				"openSource": {"author": "Some Org"},
+			},
+		}, optionValues)
+	})
+
+	t.Run("explicit values take precedence over global values", func(t *testing.T) {
+		optionValues, err := loadValueFromTestFile(t, newGT(), `---
+base:
+    projectName: someProject
+    moduleName: github.com/other/project
+`)
+		require.NoError(t, err)
+		require.Equal(t, "github.com/other/project", optionValues.Base["moduleName"])
+	})
+
+	t.Run("global values are used as defaults interactively", func(t *testing.T) {
+		gt := newGT()
+		gt.InScanner = bufio.NewScanner(strings.NewReader("\n\n\n"))
+
+		optionValues, err := gt.LoadConfigValuesInteractively()
+		require.NoError(t, err)
+		require.Equal(t, "github.com/org/project", optionValues.Base["moduleName"])
+		require.Equal(t, "Some Org", optionValues.Extensions["openSource"]["author"])
+	})
+
+	t.Run("missing global config is no error", func(t *testing.T) {
+		t.Setenv(gotemplate.GlobalConfigDirEnv, t.TempDir())
+
+		optionValues, err := loadValueFromTestFile(t, newGT(), `---
+base:
+    projectName: someProject
+    moduleName: github.com/user/project
+`)
+		require.NoError(t, err)
+		require.Equal(t, "Marty Mc Fly", optionValues.Extensions["openSource"]["author"])
+	})
+}
+
+func TestGT_ErrorFormatter(t *testing.T) {
+	out := &bytes.Buffer{}
+	gt := gotemplate.GT{
+		Streams: gotemplate.Streams{Out: out, Err: out},
+		Options: &gotemplate.Options{
+			Base: []gotemplate.Option{
+				gotemplate.NewOption(
+					optionName,
+					"description",
+					gotemplate.StaticValue("theDefault"),
+					gotemplate.WithValidator(gotemplate.RegexValidator(`^[a-z]+$`, "only lowercase letters")),
+				),
+			},
+		},
+		ErrorFormatter: func(optionName string, err error) error {
+			return fmt.Errorf("[%s] %w", optionName, err)
+		},
+	}
+
+	t.Run("formats errors of files", func(t *testing.T) {
+		_, err := loadValueFromTestFile(t, &gt, fmt.Sprintf(`---
+base:
+    %s: "NOT_VALID"`, optionName))
+
+		var errInvalidPattern *gotemplate.ErrInvalidPattern
+		require.ErrorAs(t, err, &errInvalidPattern)
+		require.True(t, strings.HasPrefix(err.Error(), fmt.Sprintf("[%s] ", optionName)))
+	})
+
+	t.Run("formats printed errors", func(t *testing.T) {
+		gt.InScanner = bufio.NewScanner(strings.NewReader("NOT_VALID\nvalid\n"))
+
+		_, err := gt.LoadConfigValuesInteractively()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), fmt.Sprintf("Validation failed: [%s] ", optionName))
+	})
+}
+
+func TestGT_AllowedValues(t *testing.T) {
+	regions := map[string][]string{
+		"aws":   {"eu-central-1", "us-east-1"},
+		"azure": {"westeurope", "eastus"},
+	}
+
+	out := &bytes.Buffer{}
+	gt := gotemplate.GT{
+		Streams: gotemplate.Streams{Out: out, Err: out},
+		Options: &gotemplate.Options{
+			Base: []gotemplate.Option{
+				gotemplate.NewOption("cloud", "description", gotemplate.StaticValue("aws"),
+					gotemplate.WithAllowedValues("aws", "azure"),
+				),
+				gotemplate.NewOption("region", "description", gotemplate.StaticValue("eu-central-1"),
+					gotemplate.WithAllowedValuesFunc(func(vals *gotemplate.OptionValues) []string {
+						return regions[vals.Base["cloud"].(string)]
+					}),
+				),
+			},
+		},
+	}
+
+	t.Run("allowed values depend on other values in files", func(t *testing.T) {
+		optionValues, err := loadValueFromTestFile(t, &gt, `---
+base:
+    cloud: azure
+    region: westeurope
+`)
+		require.NoError(t, err)
+		require.Equal(t, "westeurope", optionValues.Base["region"])
+
+		_, err = loadValueFromTestFile(t, &gt, `---
+base:
+    cloud: azure
+    region: us-east-1
+`)
+		require.ErrorIs(t, err, gotemplate.ErrMalformedInput)
+		require.Contains(t, err.Error(), "us-east-1: value not allowed (allowed: westeurope, eastus)")
+	})
+
+	t.Run("allowed values depend on earlier inputs interactively", func(t *testing.T) {
+		gt.InScanner = bufio.NewScanner(strings.NewReader("azure\nus-east-1\neastus\n"))
+
+		optionValues, err := gt.LoadConfigValuesInteractively()
+		require.NoError(t, err)
+		require.Equal(t, "eastus", optionValues.Base["region"])
+		require.Contains(t, out.String(), "region [westeurope|eastus]")
+		require.Contains(t, out.String(), "Validation failed: us-east-1: value not allowed")
+	})
+}
+
+func TestGT_ValidateOptionValues(t *testing.T) {
+	gt := gotemplate.GT{
+		Options: &gotemplate.Options{
+			Extensions: []gotemplate.Category{
+				{
+					Name: "tls",
+					Options: []gotemplate.Option{
+						gotemplate.NewOption("enableTLS", "description", gotemplate.StaticValue(false)),
+						gotemplate.NewOption("certPath", "description", gotemplate.StaticValue("")),
+					},
+				},
+			},
+			Assertions: []gotemplate.Assertion{
+				{
+					Condition: `implies .Extensions.tls.enableTLS (ne .Extensions.tls.certPath "")`,
+					Message:   "certPath is required if TLS is enabled",
+				},
+			},
+		},
+	}
+
+	t.Run("error if an assertion fails", func(t *testing.T) {
+		_, err := loadValueFromTestFile(t, &gt, `---
+extensions:
+    tls:
+        enableTLS: true
+`)
+		require.ErrorIs(t, err, gotemplate.ErrAssertionFailed)
+		require.Contains(t, err.Error(), "certPath is required if TLS is enabled")
+	})
+
+	t.Run("no error if all assertions hold", func(t *testing.T) {
+		_, err := loadValueFromTestFile(t, &gt, `---
+extensions:
+    tls:
+        enableTLS: true
+        certPath: /etc/tls/cert.pem
+`)
+		require.NoError(t, err)
+
+		_, err = loadValueFromTestFile(t, &gt, "")
+		require.NoError(t, err)
+	})
+}
+
+func TestGT_SummarizeConfig(t *testing.T) {
+	gt := gotemplate.GT{
+		Options: &gotemplate.Options{
+			Base: []gotemplate.Option{
+				gotemplate.NewOption("projectName", "description", gotemplate.StaticValue("project")),
+			},
+			Extensions: []gotemplate.Category{
+				{
+					Name: "ci",
+					Options: []gotemplate.Option{
+						gotemplate.NewOption("github", "description", gotemplate.StaticValue(false)),
+						gotemplate.NewOption("gitlab", "description", gotemplate.StaticValue(false)),
+					},
+				},
+			},
+		},
+	}
+
+	summary, err := gt.SummarizeConfig(writeTestFile(t, `---
+base:
+    projectName: someProject
+extensions:
+    ci:
+        github: true
+        jenkins: true
+`))
+
 	require.NoError(t, err)
+	require.Equal(t, []string{"ci.github"}, summary.Enabled)
+	require.Equal(t, []string{"ci.gitlab"}, summary.Disabled)
+	require.Equal(t, []string{"unknown option ci.jenkins is ignored"}, summary.Warnings)
+}
+
+func TestGT_ValidateConfigDir(t *testing.T) {
+	gt := gotemplate.GT{
+		Options: &gotemplate.Options{
+			Base: []gotemplate.Option{
+				gotemplate.NewOption("projectName", "description", gotemplate.StaticValue("project"),
+					gotemplate.WithValidator(gotemplate.RegexValidator(`^[a-z]+$`, "only lowercase letters allowed"))),
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"valid.yml":          "base:\n    projectName: valid\n",
+		"nested/valid.yaml":  "base:\n    projectName: nested\n",
+		"malformed.yml":      "base:\n    projectName: Invalid\n",
+		"missing.yml":        "base: {}\n",
+		"ignored/readme.txt": "not a config file",
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(path.Join(dir, path.Dir(name)), os.ModePerm))
+		require.NoError(t, os.WriteFile(path.Join(dir, name), []byte(content), os.ModePerm))
+	}
+
+	results := gt.ValidateConfigDir(dir)
+
+	require.Len(t, results, 4)
+	require.NoError(t, results["valid.yml"])
+	require.NoError(t, results[path.Join("nested", "valid.yaml")])
+	require.ErrorIs(t, results["malformed.yml"], gotemplate.ErrMalformedInput)
+	require.ErrorIs(t, results["missing.yml"], gotemplate.ErrParameterNotSet)
+}
+
+func TestGT_ExplainOptions(t *testing.T) {
+	gt := gotemplate.GT{
+		Options: &gotemplate.Options{
+			Base: []gotemplate.Option{
+				gotemplate.NewOption("projectName", "description", gotemplate.StaticValue("project")),
+			},
+			Extensions: []gotemplate.Category{
+				{
+					Name: "grpc",
+					Options: []gotemplate.Option{
+						gotemplate.NewOption("base", "description", gotemplate.StaticValue(false)),
+						gotemplate.NewOption("gateway", "description", gotemplate.StaticValue(false),
+							gotemplate.WithDependsOn("grpc.base")),
+						gotemplate.NewOption("hidden", "description", gotemplate.StaticValue(false),
+							gotemplate.WithShouldDisplay(gotemplate.BoolValue(false))),
+					},
+				},
+				{
+					Name:          "docs",
+					ShouldDisplay: gotemplate.BoolValue(false),
+					Options: []gotemplate.Option{
+						gotemplate.NewOption("enabled", "description", gotemplate.StaticValue(false)),
+					},
+				},
+			},
+		},
+	}
+
+	values := &gotemplate.OptionValues{
+		Base: gotemplate.OptionNameToValue{"projectName": "project"},
+		Extensions: map[string]gotemplate.OptionNameToValue{
+			"grpc": {"base": false},
+		},
+	}
+
+	require.Equal(t, []gotemplate.OptionExplanation{
+		{Name: "projectName", Displayed: true},
+		{Name: "grpc.base", Displayed: true},
+		{
+			Name:            "grpc.gateway",
+			UnmetDependency: "grpc.base",
+			Reason:          "depends on grpc.base which is not enabled",
+		},
+		{Name: "grpc.hidden", Reason: "ShouldDisplay is false for the current values"},
+		{Name: "docs.enabled", Reason: "category docs is not displayed"},
+	}, gt.ExplainOptions(values))
 
-	return gt.LoadConfigValuesFromFile(testFile)
+	values.Extensions["grpc"]["base"] = true
+	explanations := gt.ExplainOptions(values)
+	require.Equal(t, gotemplate.OptionExplanation{Name: "grpc.gateway", Displayed: true}, explanations[2])
+}
+
+func loadValueFromTestFile(t *testing.T, gt *gotemplate.GT, contents string) (*gotemplate.OptionValues, error) {
+	return gt.LoadConfigValuesFromFile(writeTestFile(t, contents))
 }
 
 func TestGT_LoadConfigValuesInteractively(t *testing.T) {
@@ -300,6 +766,8 @@ func TestGT_LoadConfigValuesInteractively(t *testing.T) {
 				},
 			},
 		}
+		// the following tests only use base options
+		t.Cleanup(func() { gt.Options.Extensions = nil })
 
 		optionValues, err := gt.LoadConfigValuesInteractively()
 		require.NoError(t, err)
@@ -365,24 +833,60 @@ func TestGT_LoadConfigValuesInteractively(t *testing.T) {
 		require.Contains(t, out.String(), "WARNING")
 	})
 
-	t.Run("retries to get value on error", func(t *testing.T) {
+	t.Run("requires explicit input if the default is not valid", func(t *testing.T) {
 		out := &bytes.Buffer{}
 		gt.Err = out
-		gt.InScanner = bufio.NewScanner(strings.NewReader(optionValue + "not a bool\ntrue\n"))
+		gt.InScanner = bufio.NewScanner(strings.NewReader("\n\nentered-value\n"))
 		gt.Options.Base = []gotemplate.Option{
-			gotemplate.NewOption(optionName, "description", gotemplate.StaticValue(false)),
+			gotemplate.NewOption(
+				optionName,
+				"description",
+				gotemplate.StaticValue("INVALID_DEFAULT"),
+				gotemplate.WithValidator(gotemplate.RegexValidator(`^[a-z-]+$`, "only lowercase letters and dashes")),
+			),
 		}
 
 		optionValues, err := gt.LoadConfigValuesInteractively()
 		require.NoError(t, err)
-		require.Equal(t, gotemplate.OptionNameToValue{optionName: true}, optionValues.Base)
-		require.Contains(t, out.String(), "WARNING")
+		require.Equal(t, gotemplate.OptionNameToValue{optionName: "entered-value"}, optionValues.Base)
+		require.Equal(t, 3, strings.Count(out.String(), "The default value is not valid"), "should be prompted until a value is entered")
+		require.Contains(t, out.String(), gotemplate.ErrParameterNotSet.Error())
 	})
 
-	t.Run("renders dynamic values correctly", func(t *testing.T) {
-		templateOptionName := "templatedOption"
-		// simulate setting a value for first option and use default for next
-		gt.InScanner = bufio.NewScanner(strings.NewReader(optionValue + "\n\n"))
+	t.Run("error if the default is not valid and the input is closed", func(t *testing.T) {
+		gt.Err = &bytes.Buffer{}
+		gt.InScanner = bufio.NewScanner(strings.NewReader(""))
+		gt.Options.Base = []gotemplate.Option{
+			gotemplate.NewOption(
+				optionName,
+				"description",
+				gotemplate.StaticValue("INVALID_DEFAULT"),
+				gotemplate.WithValidator(gotemplate.RegexValidator(`^[a-z-]+$`, "only lowercase letters and dashes")),
+			),
+		}
+
+		_, err := gt.LoadConfigValuesInteractively()
+		require.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("retries to get value on error", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		gt.Err = out
+		gt.InScanner = bufio.NewScanner(strings.NewReader(optionValue + "not a bool\ntrue\n"))
+		gt.Options.Base = []gotemplate.Option{
+			gotemplate.NewOption(optionName, "description", gotemplate.StaticValue(false)),
+		}
+
+		optionValues, err := gt.LoadConfigValuesInteractively()
+		require.NoError(t, err)
+		require.Equal(t, gotemplate.OptionNameToValue{optionName: true}, optionValues.Base)
+		require.Contains(t, out.String(), "WARNING")
+	})
+
+	t.Run("renders dynamic values correctly", func(t *testing.T) {
+		templateOptionName := "templatedOption"
+		// simulate setting a value for first option and use default for next
+		gt.InScanner = bufio.NewScanner(strings.NewReader(optionValue + "\n\n"))
 		gt.Options.Base = []gotemplate.Option{
 			gotemplate.NewOption(
 				optionName,
@@ -482,26 +986,350 @@ func TestGT_LoadConfigValuesInteractively(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("reads the value again if it can't be parsed", func(t *testing.T) {
+		out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+		gt := gotemplate.GT{
+			Streams: gotemplate.Streams{
+				Out:       out,
+				Err:       errOut,
+				InScanner: bufio.NewScanner(strings.NewReader("two\n2\n")),
+			},
+			Options: &gotemplate.Options{
+				Base: []gotemplate.Option{
+					gotemplate.NewOption("count", "the count description", gotemplate.StaticValue(1)),
+				},
+			},
+		}
+
+		optionValues, err := gt.LoadConfigValuesInteractively()
+		require.NoError(t, err)
+		require.Equal(t, gotemplate.OptionNameToValue{"count": 2}, optionValues.Base)
+		require.Contains(t, errOut.String(), `expected an integer but got "two", please try again`)
+		require.Equal(t, 1, strings.Count(out.String(), "the count description"), "description should not be printed again")
+		require.Equal(t, 2, strings.Count(out.String(), "count: (1)"), "input line should be printed again")
+	})
+
+	t.Run("resumes an interrupted session from the recovery file", func(t *testing.T) {
+		recoveryFile := path.Join(t.TempDir(), "session.yml")
+		newGT := func(in io.Reader) *gotemplate.GT {
+			return &gotemplate.GT{
+				Streams: gotemplate.Streams{
+					Out:       &bytes.Buffer{},
+					Err:       &bytes.Buffer{},
+					InScanner: bufio.NewScanner(in),
+				},
+				Options: &gotemplate.Options{
+					Base: []gotemplate.Option{
+						gotemplate.NewOption("first", "description", gotemplate.StaticValue("")),
+						gotemplate.NewOption("second", "description", gotemplate.StaticValue(1)),
+					},
+				},
+				RecoveryFile: recoveryFile,
+			}
+		}
+
+		// the input is interrupted after the first answer
+		errInterrupted := errors.New("interrupted")
+		_, err := newGT(io.MultiReader(strings.NewReader("kept\n"), iotest.ErrReader(errInterrupted))).
+			LoadConfigValuesInteractively()
+		require.ErrorIs(t, err, errInterrupted)
+		require.FileExists(t, recoveryFile)
+
+		// after confirming to resume only the second option is asked again
+		optionValues, err := newGT(strings.NewReader("y\n2\n")).LoadConfigValuesInteractively()
+		require.NoError(t, err)
+		require.Equal(t, gotemplate.OptionNameToValue{"first": "kept", "second": 2}, optionValues.Base)
+		require.NoFileExists(t, recoveryFile, "recovery file should be removed on success")
+	})
+
+	t.Run("asks again for all values if resuming is declined", func(t *testing.T) {
+		recoveryFile := path.Join(t.TempDir(), "session.yml")
+		require.NoError(t, os.WriteFile(recoveryFile, []byte("base:\n  first: other-project\n"), 0o600))
+
+		gt := gotemplate.GT{
+			Streams: gotemplate.Streams{
+				Out:       &bytes.Buffer{},
+				InScanner: bufio.NewScanner(strings.NewReader("n\nnew\n")),
+			},
+			Options: &gotemplate.Options{
+				Base: []gotemplate.Option{
+					gotemplate.NewOption("first", "description", gotemplate.StaticValue("")),
+				},
+			},
+			RecoveryFile: recoveryFile,
+		}
+
+		optionValues, err := gt.LoadConfigValuesInteractively()
+		require.NoError(t, err)
+		require.Equal(t, gotemplate.OptionNameToValue{"first": "new"}, optionValues.Base)
+	})
+
+	t.Run("asks again for recovered values that are not valid", func(t *testing.T) {
+		recoveryFile := path.Join(t.TempDir(), "session.yml")
+		require.NoError(t, os.WriteFile(recoveryFile, []byte("base:\n  name: NOT_VALID\n  count: two\n"), 0o600))
+
+		errOut := &bytes.Buffer{}
+		gt := gotemplate.GT{
+			Streams: gotemplate.Streams{
+				Out:       &bytes.Buffer{},
+				Err:       errOut,
+				InScanner: bufio.NewScanner(strings.NewReader("y\nvalid\n2\n")),
+			},
+			Options: &gotemplate.Options{
+				Base: []gotemplate.Option{
+					gotemplate.NewOption("name", "description", gotemplate.StaticValue("default"),
+						gotemplate.WithValidator(gotemplate.RegexValidator(`^[a-z]+$`, "only lowercase letters")),
+					),
+					gotemplate.NewOption("count", "description", gotemplate.StaticValue(1)),
+				},
+			},
+			RecoveryFile: recoveryFile,
+		}
+
+		optionValues, err := gt.LoadConfigValuesInteractively()
+		require.NoError(t, err)
+		require.Equal(t, gotemplate.OptionNameToValue{"name": "valid", "count": 2}, optionValues.Base)
+		require.Equal(t, 2, strings.Count(errOut.String(), "The recovered value is not valid"))
+	})
+
+	t.Run("hides and orders categories", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		gt := gotemplate.GT{
+			Streams: gotemplate.Streams{
+				Out: out,
+				// grpc base option, first option of the docs category, first option of the ci category
+				InScanner: bufio.NewScanner(strings.NewReader("false\ntrue\n2\n")),
+			},
+			Options: &gotemplate.Options{
+				Base: []gotemplate.Option{
+					gotemplate.NewOption("grpc", "description", gotemplate.StaticValue(true)),
+				},
+				Extensions: []gotemplate.Category{
+					{
+						Name: "ci",
+						Options: []gotemplate.Option{
+							gotemplate.NewOption("provider", "description", gotemplate.StaticValue(1)),
+						},
+					},
+					{
+						Name: "grpc",
+						Options: []gotemplate.Option{
+							gotemplate.NewOption("gateway", "description", gotemplate.StaticValue(true)),
+						},
+						ShouldDisplay: gotemplate.DynamicBoolValue(func(vals *gotemplate.OptionValues) bool {
+							return vals.Base["grpc"].(bool)
+						}),
+					},
+					{
+						Name: "docs",
+						Options: []gotemplate.Option{
+							gotemplate.NewOption("enabled", "description", gotemplate.StaticValue(false)),
+						},
+						Order: func(*gotemplate.OptionValues) int { return -1 },
+					},
+				},
+			},
+		}
+
+		optionValues, err := gt.LoadConfigValuesInteractively()
+		require.NoError(t, err)
+		require.Equal(t, map[string]gotemplate.OptionNameToValue{
+			"ci":   {"provider": 2},
+			"grpc": {"gateway": true},
+			"docs": {"enabled": true},
+		}, optionValues.Extensions)
+		require.NotContains(t, out.String(), `CATEGORY: "GRPC"`)
+		require.Less(t, strings.Index(out.String(), `CATEGORY: "DOCS"`), strings.Index(out.String(), `CATEGORY: "CI"`))
+	})
+
+	t.Run("recalls the last entered value of the same type", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		gt := gotemplate.GT{
+			Streams: gotemplate.Streams{
+				Out:       out,
+				Err:       out,
+				InScanner: bufio.NewScanner(strings.NewReader("!!\ninternal/first\ntrue\n!!\n!!\n")),
+			},
+			Options: &gotemplate.Options{
+				Base: []gotemplate.Option{
+					gotemplate.NewOption("first", "description", gotemplate.StaticValue("")),
+					gotemplate.NewOption("bool", "description", gotemplate.StaticValue(false)),
+					gotemplate.NewOption("second", "description", gotemplate.StaticValue("")),
+					gotemplate.NewOption("otherBool", "description", gotemplate.StaticValue(false)),
+				},
+			},
+		}
+
+		optionValues, err := gt.LoadConfigValuesInteractively()
+		require.NoError(t, err)
+		require.Equal(t, gotemplate.OptionNameToValue{
+			"first":     "internal/first",
+			"bool":      true,
+			"second":    "internal/first",
+			"otherBool": true,
+		}, optionValues.Base)
+		require.Contains(t, out.String(), "No previous value to recall")
+	})
 }
 
-func TestGT_InitNewProject(t *testing.T) {
-	// initialize template.FuncMap
-	gt := gotemplate.New()
-	gt.Streams.Out = &bytes.Buffer{}
+func TestGT_RecordInput(t *testing.T) {
+	newGT := func(input io.Reader) *gotemplate.GT {
+		return &gotemplate.GT{
+			Streams: gotemplate.Streams{
+				Out:       &bytes.Buffer{},
+				Err:       &bytes.Buffer{},
+				InScanner: bufio.NewScanner(input),
+			},
+			Options: &gotemplate.Options{
+				Base: []gotemplate.Option{
+					gotemplate.NewOption(optionName, "description", gotemplate.StaticValue("theDefault"),
+						gotemplate.WithValidator(gotemplate.RegexValidator(`^[a-z]+$`, "only lowercase letters")),
+					),
+					gotemplate.NewOption("number", "description", gotemplate.StaticValue(1)),
+				},
+				Extensions: []gotemplate.Category{
+					{
+						Name: "category",
+						Options: []gotemplate.Option{
+							gotemplate.NewOption("enabled", "description", gotemplate.StaticValue(false)),
+						},
+					},
+				},
+			},
+		}
+	}
 
-	testValuesBytes, err := os.ReadFile("./testdata/values.yml")
+	recorded := &bytes.Buffer{}
+	gt := newGT(strings.NewReader("NOT_VALID\nvalid\n\ntrue\n"))
+	gt.RecordInput = recorded
+
+	optionValues, err := gt.LoadConfigValuesInteractively()
 	require.NoError(t, err)
+	require.Equal(t, "NOT_VALID\nvalid\n\ntrue\n", recorded.String())
 
-	var optionValues gotemplate.OptionValues
-	err = yaml.Unmarshal(testValuesBytes, &optionValues)
+	replayedValues, err := newGT(recorded).LoadConfigValuesInteractively()
 	require.NoError(t, err)
+	require.Equal(t, optionValues, replayedValues)
+}
+
+func TestGT_InitNewProject_Confirm(t *testing.T) {
+	generate := func(input string, confirm, assumeYes bool) (*gotemplate.NewRepositoryOptions, string, error) {
+		out := &bytes.Buffer{}
+		gt := newTemplateTestGT(fstest.MapFS{
+			"README.md": &fstest.MapFile{Data: []byte("# {{ .Base.projectName }}")},
+		})
+		gt.Out = out
+		gt.InScanner = bufio.NewScanner(strings.NewReader(input))
+
+		opts := newTemplateTestOpts(t)
+		opts.Confirm = confirm
+		opts.AssumeYes = assumeYes
+
+		_, err := gt.InitNewProject(opts)
+		return opts, out.String(), err
+	}
+
+	t.Run("aborts without creating the target dir on no", func(t *testing.T) {
+		opts, out, err := generate("n\n", true, false)
+		require.ErrorIs(t, err, gotemplate.ErrAborted)
+		require.Contains(t, out, "projectSlug: project", "should print the values")
+		require.NoDirExists(t, getTargetDir(opts.OutputDir, opts))
+	})
+
+	t.Run("generates the project on yes", func(t *testing.T) {
+		opts, _, err := generate("y\n", true, false)
+		require.NoError(t, err)
+		require.FileExists(t, path.Join(getTargetDir(opts.OutputDir, opts), "README.md"))
+	})
+
+	t.Run("does not ask with AssumeYes", func(t *testing.T) {
+		opts, out, err := generate("", true, true)
+		require.NoError(t, err)
+		require.NotContains(t, out, "Proceed?")
+		require.FileExists(t, path.Join(getTargetDir(opts.OutputDir, opts), "README.md"))
+	})
+
+	t.Run("does not ask if no confirmation is needed", func(t *testing.T) {
+		opts, out, err := generate("", false, false)
+		require.NoError(t, err)
+		require.NotContains(t, out, "Proceed?")
+		require.FileExists(t, path.Join(getTargetDir(opts.OutputDir, opts), "README.md"))
+	})
+}
+
+func TestGT_RenderPrompt(t *testing.T) {
+	gt := gotemplate.GT{
+		Streams: gotemplate.Streams{Out: &bytes.Buffer{}},
+		Options: &gotemplate.Options{
+			Base: []gotemplate.Option{
+				gotemplate.NewOption(optionName, "some description", gotemplate.StaticValue("theDefault")),
+			},
+			Extensions: []gotemplate.Category{
+				{
+					Name: "category",
+					Options: []gotemplate.Option{
+						gotemplate.NewOption(
+							"dynamic",
+							"description",
+							gotemplate.DynamicValue(func(vals *gotemplate.OptionValues) interface{} {
+								return vals.Base[optionName].(string) + "-dynamic"
+							}),
+						),
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("renders base option", func(t *testing.T) {
+		prompt, err := gt.RenderPrompt(optionName, gotemplate.NewOptionValues())
+		require.NoError(t, err)
+		require.Contains(t, prompt, optionName)
+		require.Contains(t, prompt, "some description")
+		require.Contains(t, prompt, "theDefault")
+	})
+
+	t.Run("renders extension option with current values", func(t *testing.T) {
+		values := gotemplate.NewOptionValues()
+		values.Base[optionName] = "value"
+
+		prompt, err := gt.RenderPrompt("category.dynamic", values)
+		require.NoError(t, err)
+		require.Contains(t, prompt, "dynamic")
+		require.Contains(t, prompt, "value-dynamic")
+	})
+
+	t.Run("renders default of the global config", func(t *testing.T) {
+		configDir := t.TempDir()
+		t.Setenv(gotemplate.GlobalConfigDirEnv, configDir)
+		err := os.WriteFile(path.Join(configDir, "config.yml"), []byte(fmt.Sprintf("base:\n  %s: globalDefault\n", optionName)), os.ModePerm)
+		require.NoError(t, err)
+
+		prompt, err := gt.RenderPrompt(optionName, gotemplate.NewOptionValues())
+		require.NoError(t, err)
+		require.Contains(t, prompt, "globalDefault")
+		require.NotContains(t, prompt, "theDefault")
+	})
+
+	t.Run("error for unknown option", func(t *testing.T) {
+		_, err := gt.RenderPrompt("unknown", gotemplate.NewOptionValues())
+		require.ErrorIs(t, err, gotemplate.ErrUnknownOption)
+	})
+}
+
+func TestGT_InitNewProject(t *testing.T) {
+	// initialize template.FuncMap
+	gt := gotemplate.New()
+	gt.Streams.Out = &bytes.Buffer{}
+	gt.Streams.Err = &bytes.Buffer{}
 
-	opts := &gotemplate.NewRepositoryOptions{OptionValues: &optionValues}
+	opts := &gotemplate.NewRepositoryOptions{OptionValues: newTestValues(t, nil)}
 	t.Run("generates folder in target dir and initializes it with go.mod and .git", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		opts.OutputDir = tmpDir
 
-		err = gt.InitNewProject(opts)
+		_, err := gt.InitNewProject(opts)
 		require.NoError(t, err)
 
 		_, err = os.Stat(path.Join(getTargetDir(tmpDir, opts), ".git"))
@@ -515,7 +1343,7 @@ func TestGT_InitNewProject(t *testing.T) {
 		tmpDir := t.TempDir()
 		opts.OutputDir = tmpDir
 
-		err = gt.InitNewProject(opts)
+		_, err := gt.InitNewProject(opts)
 		require.NoError(t, err)
 
 		testItems := []string{".gitignore", "pkg", "internal", ".golangci.yml"}
@@ -529,7 +1357,7 @@ func TestGT_InitNewProject(t *testing.T) {
 		tmpDir := t.TempDir()
 		opts.OutputDir = tmpDir
 
-		err := gt.InitNewProject(opts)
+		_, err := gt.InitNewProject(opts)
 		require.NoError(t, err)
 
 		err = filepath.WalkDir(getTargetDir(tmpDir, opts), func(path string, d fs.DirEntry, err error) error {
@@ -559,75 +1387,1022 @@ func TestGT_InitNewProject(t *testing.T) {
 		require.NoError(t, err)
 	})
 
-	t.Run("error if target dir already exists", func(t *testing.T) {
+	t.Run("writes project to archive without .git by default", func(t *testing.T) {
 		tmpDir := t.TempDir()
-		opts.OutputDir = tmpDir
+		archiveFile := path.Join(tmpDir, "project.tar.gz")
 
-		err := os.MkdirAll(getTargetDir(tmpDir, opts), os.ModePerm)
+		_, err := gt.InitNewProject(&gotemplate.NewRepositoryOptions{
+			OptionValues: opts.OptionValues,
+			ArchiveFile:  archiveFile,
+		})
 		require.NoError(t, err)
 
-		err = gt.InitNewProject(opts)
-		require.Error(t, err)
+		entries := readArchiveEntries(t, archiveFile)
+		require.Contains(t, entries, path.Join(opts.OptionValues.Base[targetDirOptionName].(string), "go.mod"))
+		for _, entry := range entries {
+			require.NotContains(t, strings.Split(entry, "/"), ".git", "archive should not contain .git")
+		}
 	})
 
-	t.Run("removes all files on error", func(t *testing.T) {
+	t.Run("generates flat layout", func(t *testing.T) {
 		tmpDir := t.TempDir()
-		// force error with empty values
-		err = gt.InitNewProject(
-			&gotemplate.NewRepositoryOptions{
-				OutputDir: tmpDir,
-				OptionValues: &gotemplate.OptionValues{
-					Base: gotemplate.OptionNameToValue{
-						targetDirOptionName: "testingDir",
-					},
-				}},
-		)
-		require.Error(t, err)
 
-		_, err := os.Stat(getTargetDir(tmpDir, opts))
+		flatOpts := &gotemplate.NewRepositoryOptions{
+			OutputDir: tmpDir,
+			OptionValues: newTestValues(t, map[string]gotemplate.OptionNameToValue{
+				"base": {"layout": "flat"},
+				"grpc": {"base": false},
+			}),
+		}
+
+		_, err := gt.InitNewProject(flatOpts)
+		require.NoError(t, err)
+
+		_, err = os.Stat(path.Join(getTargetDir(tmpDir, flatOpts), "main.go"))
+		require.NoError(t, err)
+
+		_, err = os.Stat(path.Join(getTargetDir(tmpDir, flatOpts), "cmd"))
 		require.ErrorIs(t, err, os.ErrNotExist)
 	})
 
-	t.Run("postHook not executed if value not set", func(t *testing.T) {
+	t.Run("writes toolchain directive to go.mod", func(t *testing.T) {
+		goSemver, err := gocli.Semver()
+		require.NoError(t, err)
+		if goSemver.LessThan(semver.MustParse("1.21")) {
+			t.Skip("go version does not support toolchains")
+		}
+
 		tmpDir := t.TempDir()
-		opts.OutputDir = tmpDir
 
-		postHookTriggered := false
-		gt.Options.Base = append(gt.Options.Base, gotemplate.NewOption(
-			"testOption",
-			"description",
-			gotemplate.StaticValue(true),
-			gotemplate.WithPosthook(func(value interface{}, optionValues *gotemplate.OptionValues, targetDir string) error {
-				postHookTriggered = true
-				return nil
-			}),
-		))
+		toolchainOpts := &gotemplate.NewRepositoryOptions{
+			OutputDir:    tmpDir,
+			OptionValues: newTestValues(t, map[string]gotemplate.OptionNameToValue{"go": {"toolchain": "go1.21.0"}}),
+		}
+		_, err = gt.InitNewProject(toolchainOpts)
+		require.NoError(t, err)
 
-		err := gt.InitNewProject(opts)
+		goMod, err := os.ReadFile(path.Join(getTargetDir(tmpDir, toolchainOpts), "go.mod"))
 		require.NoError(t, err)
-		require.False(t, postHookTriggered, "postHook should not be triggered")
+		require.Contains(t, string(goMod), "toolchain go1.21.0")
 	})
 
-	t.Run("postHook is executed if value is set", func(t *testing.T) {
+	t.Run("writes dependabot config for used ecosystems", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		opts.OutputDir = tmpDir
-		opts.OptionValues.Base["testOption"] = true
 
-		postHookTriggered := false
-		gt.Options.Base = append(gt.Options.Base, gotemplate.NewOption(
-			"testOption",
-			"description",
-			gotemplate.StaticValue(false),
-			gotemplate.WithPosthook(func(value interface{}, optionValues *gotemplate.OptionValues, targetDir string) error {
-				postHookTriggered = true
-				return nil
-			}),
-		))
+		_, err := gt.InitNewProject(opts)
+		require.NoError(t, err)
 
-		err := gt.InitNewProject(opts)
+		dependabot, err := os.ReadFile(path.Join(getTargetDir(tmpDir, opts), ".github", "dependabot.yml"))
 		require.NoError(t, err)
-		require.True(t, postHookTriggered, "postHook should be triggered")
+		require.Contains(t, string(dependabot), "package-ecosystem: gomod")
+		require.Contains(t, string(dependabot), "package-ecosystem: docker")
+		require.Contains(t, string(dependabot), "package-ecosystem: github-actions")
+	})
+
+	t.Run("initializes submodules in a workspace", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		workspaceOpts := &gotemplate.NewRepositoryOptions{
+			OutputDir:    tmpDir,
+			OptionValues: newTestValues(t, map[string]gotemplate.OptionNameToValue{"go": {"submodules": "libs/a,tools"}}),
+		}
+		_, err := gt.InitNewProject(workspaceOpts)
+		require.NoError(t, err)
+
+		goWork, err := os.ReadFile(path.Join(getTargetDir(tmpDir, workspaceOpts), "go.work"))
+		require.NoError(t, err)
+		require.Contains(t, string(goWork), "./libs/a")
+		require.Contains(t, string(goWork), "./tools")
+
+		goMod, err := os.ReadFile(path.Join(getTargetDir(tmpDir, workspaceOpts), "libs", "a", "go.mod"))
+		require.NoError(t, err)
+		require.Contains(t, string(goMod), "module github.com/fake/testing/libs/a")
+	})
+
+	t.Run("writes Dockerfile only if docker is enabled", func(t *testing.T) {
+		for _, enabled := range []bool{true, false} {
+			tmpDir := t.TempDir()
+
+			dockerOpts := &gotemplate.NewRepositoryOptions{
+				OutputDir:    tmpDir,
+				OptionValues: newTestValues(t, map[string]gotemplate.OptionNameToValue{"docker": {"base": enabled}}),
+			}
+			_, err := gt.InitNewProject(dockerOpts)
+			require.NoError(t, err)
+
+			dockerfile, err := os.ReadFile(path.Join(getTargetDir(tmpDir, dockerOpts), "Dockerfile"))
+			_, dockerignoreErr := os.Stat(path.Join(getTargetDir(tmpDir, dockerOpts), ".dockerignore"))
+			if !enabled {
+				require.ErrorIs(t, err, os.ErrNotExist)
+				require.ErrorIs(t, dockerignoreErr, os.ErrNotExist)
+				continue
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, dockerignoreErr)
+			require.Contains(t, string(dockerfile), "-o /app/testing ./cmd/testing")
+			require.Contains(t, string(dockerfile), `ENTRYPOINT ["/testing"]`)
+		}
+	})
+
+	t.Run("writes .gitattributes only if enabled", func(t *testing.T) {
+		for _, enabled := range []bool{true, false} {
+			tmpDir := t.TempDir()
+
+			gitOpts := &gotemplate.NewRepositoryOptions{
+				OutputDir:    tmpDir,
+				OptionValues: newTestValues(t, map[string]gotemplate.OptionNameToValue{"git": {"gitattributes": enabled}}),
+			}
+			_, err := gt.InitNewProject(gitOpts)
+			require.NoError(t, err)
+
+			gitattributes, err := os.ReadFile(path.Join(getTargetDir(tmpDir, gitOpts), ".gitattributes"))
+			if !enabled {
+				require.ErrorIs(t, err, os.ErrNotExist)
+				continue
+			}
+
+			require.NoError(t, err)
+			require.Contains(t, string(gitattributes), "* text=auto")
+			require.Contains(t, string(gitattributes), "internal/pkg/api/pb/** linguist-generated=true")
+		}
 	})
+
+	t.Run("writes VS Code settings only if enabled", func(t *testing.T) {
+		for _, enabled := range []bool{true, false} {
+			tmpDir := t.TempDir()
+
+			vscodeOpts := &gotemplate.NewRepositoryOptions{
+				OutputDir:    tmpDir,
+				OptionValues: newTestValues(t, map[string]gotemplate.OptionNameToValue{"vscode": {"base": enabled}}),
+			}
+			_, err := gt.InitNewProject(vscodeOpts)
+			require.NoError(t, err)
+
+			targetDir := getTargetDir(tmpDir, vscodeOpts)
+			settingsBytes, err := os.ReadFile(path.Join(targetDir, ".vscode", "settings.json"))
+			gitignore, gitignoreErr := os.ReadFile(path.Join(targetDir, ".gitignore"))
+			require.NoError(t, gitignoreErr)
+			if !enabled {
+				require.ErrorIs(t, err, os.ErrNotExist)
+				require.NotContains(t, string(gitignore), "!.vscode/settings.json")
+				continue
+			}
+
+			require.NoError(t, err)
+			require.Contains(t, string(gitignore), "!.vscode/settings.json")
+
+			var settings map[string]interface{}
+			require.NoError(t, json.Unmarshal(settingsBytes, &settings))
+			require.Equal(t, "golangci-lint", settings["go.lintTool"])
+			require.Equal(t, "github.com/fake/testing", settings["gopls"].(map[string]interface{})["formatting.local"])
+
+			extensionsBytes, err := os.ReadFile(path.Join(targetDir, ".vscode", "extensions.json"))
+			require.NoError(t, err)
+
+			var extensions struct {
+				Recommendations []string `json:"recommendations"`
+			}
+			require.NoError(t, json.Unmarshal(extensionsBytes, &extensions))
+			require.Contains(t, extensions.Recommendations, "golang.go")
+			require.Contains(t, extensions.Recommendations, "zxh404.vscode-proto3")
+		}
+	})
+
+	t.Run("writes release-please config only if enabled", func(t *testing.T) {
+		for _, enabled := range []bool{true, false} {
+			tmpDir := t.TempDir()
+
+			releaseOpts := &gotemplate.NewRepositoryOptions{
+				OutputDir:    tmpDir,
+				OptionValues: newTestValues(t, map[string]gotemplate.OptionNameToValue{"release": {"base": enabled, "version": "1.2.3"}}),
+			}
+			_, err := gt.InitNewProject(releaseOpts)
+			require.NoError(t, err)
+
+			targetDir := getTargetDir(tmpDir, releaseOpts)
+			configBytes, err := os.ReadFile(path.Join(targetDir, "release-please-config.json"))
+			_, manifestErr := os.Stat(path.Join(targetDir, ".release-please-manifest.json"))
+			_, workflowErr := os.Stat(path.Join(targetDir, ".github", "workflows", "release-please.yml"))
+			if !enabled {
+				require.ErrorIs(t, err, os.ErrNotExist)
+				require.ErrorIs(t, manifestErr, os.ErrNotExist)
+				require.ErrorIs(t, workflowErr, os.ErrNotExist)
+				continue
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, manifestErr)
+			require.NoError(t, workflowErr)
+
+			var config struct {
+				Packages map[string]struct {
+					PackageName string `json:"package-name"`
+				} `json:"packages"`
+			}
+			require.NoError(t, json.Unmarshal(configBytes, &config))
+			require.Equal(t, "github.com/fake/testing", config.Packages["."].PackageName)
+
+			manifestBytes, err := os.ReadFile(path.Join(targetDir, ".release-please-manifest.json"))
+			require.NoError(t, err)
+			require.JSONEq(t, `{".": "1.2.3"}`, string(manifestBytes))
+		}
+	})
+
+	t.Run("writes pre-commit config with hooks of enabled integrations", func(t *testing.T) {
+		for _, enabled := range []bool{true, false} {
+			tmpDir := t.TempDir()
+
+			preCommitOpts := &gotemplate.NewRepositoryOptions{
+				OutputDir: tmpDir,
+				OptionValues: newTestValues(t, map[string]gotemplate.OptionNameToValue{
+					"git":    {"preCommit": enabled},
+					"docker": {"base": false},
+				}),
+			}
+			_, err := gt.InitNewProject(preCommitOpts)
+			require.NoError(t, err)
+
+			configBytes, err := os.ReadFile(path.Join(getTargetDir(tmpDir, preCommitOpts), ".pre-commit-config.yaml"))
+			if !enabled {
+				require.ErrorIs(t, err, os.ErrNotExist)
+				continue
+			}
+
+			require.NoError(t, err)
+
+			var config struct {
+				Repos []struct {
+					Rev   string `yaml:"rev"`
+					Hooks []struct {
+						ID string `yaml:"id"`
+					} `yaml:"hooks"`
+				} `yaml:"repos"`
+			}
+			require.NoError(t, yaml.Unmarshal(configBytes, &config))
+
+			revs := map[string]string{}
+			for _, repo := range config.Repos {
+				for _, hook := range repo.Hooks {
+					revs[hook.ID] = repo.Rev
+				}
+			}
+			require.Equal(t, "v1.48.0", revs["golangci-lint"])
+			require.Contains(t, revs, "buf-lint", "grpc is enabled")
+			require.NotContains(t, revs, "hadolint-docker", "docker is disabled")
+		}
+	})
+
+	t.Run("error if target dir already exists", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		opts.OutputDir = tmpDir
+
+		err := os.MkdirAll(getTargetDir(tmpDir, opts), os.ModePerm)
+		require.NoError(t, err)
+
+		_, err = gt.InitNewProject(opts)
+		require.Error(t, err)
+	})
+
+	t.Run("removes all files on error", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		// force error with empty values
+		_, err := gt.InitNewProject(
+			&gotemplate.NewRepositoryOptions{
+				OutputDir: tmpDir,
+				OptionValues: &gotemplate.OptionValues{
+					Base: gotemplate.OptionNameToValue{
+						targetDirOptionName: "testingDir",
+					},
+				}},
+		)
+		require.Error(t, err)
+
+		_, err = os.Stat(getTargetDir(tmpDir, opts))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("postHook not executed if value not set", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		opts.OutputDir = tmpDir
+
+		postHookTriggered := false
+		gt.Options.Base = append(gt.Options.Base, gotemplate.NewOption(
+			"testOption",
+			"description",
+			gotemplate.StaticValue(true),
+			gotemplate.WithPosthook(func(value interface{}, optionValues *gotemplate.OptionValues, targetDir string) error {
+				postHookTriggered = true
+				return nil
+			}),
+		))
+
+		_, err := gt.InitNewProject(opts)
+		require.NoError(t, err)
+		require.False(t, postHookTriggered, "postHook should not be triggered")
+	})
+
+	t.Run("postHook is executed if value is set", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		opts.OutputDir = tmpDir
+		opts.OptionValues.Base["testOption"] = true
+
+		postHookTriggered := false
+		gt.Options.Base = append(gt.Options.Base, gotemplate.NewOption(
+			"testOption",
+			"description",
+			gotemplate.StaticValue(false),
+			gotemplate.WithPosthook(func(value interface{}, optionValues *gotemplate.OptionValues, targetDir string) error {
+				postHookTriggered = true
+				return nil
+			}),
+		))
+
+		_, err := gt.InitNewProject(opts)
+		require.NoError(t, err)
+		require.True(t, postHookTriggered, "postHook should be triggered")
+	})
+}
+
+func readArchiveEntries(t *testing.T, archiveFile string) []string {
+	file, err := os.Open(archiveFile)
+	require.NoError(t, err)
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	var entries []string
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+
+		entries = append(entries, strings.TrimSuffix(header.Name, "/"))
+	}
+
+	return entries
+}
+
+func TestGT_SmokeTest(t *testing.T) {
+	gt := newTemplateTestGT(fstest.MapFS{
+		"README.md": &fstest.MapFile{
+			Data: []byte(`{{ if eq .Base.projectName "broken" }}{{ fail "project can't be broken" }}{{ end }}# {{ .Base.projectName }}`),
+		},
+	})
+
+	t.Run("passes for a valid config", func(t *testing.T) {
+		configFile := writeTestFile(t, `---
+base:
+    projectName: valid
+    projectSlug: valid
+    moduleName: github.com/user/valid
+`)
+
+		require.NoError(t, gt.SmokeTest(configFile))
+	})
+
+	t.Run("fails if config triggers a template error", func(t *testing.T) {
+		configFile := writeTestFile(t, `---
+base:
+    projectName: broken
+    projectSlug: broken
+    moduleName: github.com/user/broken
+`)
+
+		err := gt.SmokeTest(configFile)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "project can't be broken")
+	})
+}
+
+func TestGT_InitNewProject_AfterRender(t *testing.T) {
+	gt := newTemplateTestGT(fstest.MapFS{
+		"README.md":  &fstest.MapFile{Data: []byte("# {{ .Base.projectName }}")},
+		"skipped.md": &fstest.MapFile{Data: []byte("skipped")},
+	})
+	gt.AfterRender = func(path string, content []byte) ([]byte, error) {
+		if path == "skipped.md" {
+			return nil, gotemplate.ErrSkipFile
+		}
+
+		return bytes.ToUpper(content), nil
+	}
+
+	opts := newTemplateTestOpts(t)
+
+	_, err := gt.InitNewProject(opts)
+	require.NoError(t, err)
+
+	readme, err := os.ReadFile(path.Join(getTargetDir(opts.OutputDir, opts), "README.md"))
+	require.NoError(t, err)
+	require.Equal(t, "# PROJECT", string(readme))
+
+	_, err = os.Stat(path.Join(getTargetDir(opts.OutputDir, opts), "skipped.md"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestGT_InitNewProject_NormalizeEditorConfig(t *testing.T) {
+	gt := newTemplateTestGT(fstest.MapFS{
+		".editorconfig": &fstest.MapFile{Data: []byte(`root = true
+
+[*]
+insert_final_newline = true
+trim_trailing_whitespace = true
+
+[*.md]
+trim_trailing_whitespace = false
+
+[*.{yml,yaml}]
+indent_style = space
+indent_size = 2
+`)},
+		"config/config.yml": &fstest.MapFile{Data: []byte("name: {{ .Base.projectName }}   \nnested:\n\tkey: value")},
+		"README.md":         &fstest.MapFile{Data: []byte("line with break  \n")},
+	})
+	gt.NormalizeEditorConfig = true
+
+	opts := newTemplateTestOpts(t)
+
+	_, err := gt.InitNewProject(opts)
+	require.NoError(t, err)
+
+	config, err := os.ReadFile(path.Join(getTargetDir(opts.OutputDir, opts), "config", "config.yml"))
+	require.NoError(t, err)
+	require.Equal(t, "name: project\nnested:\n  key: value\n", string(config))
+
+	readme, err := os.ReadFile(path.Join(getTargetDir(opts.OutputDir, opts), "README.md"))
+	require.NoError(t, err)
+	require.Equal(t, "line with break  \n", string(readme))
+}
+
+func TestGT_InitNewProject_LiteralSubstitutions(t *testing.T) {
+	gt := newTemplateTestGT(fstest.MapFS{
+		"keystore.bin.literal": &fstest.MapFile{Data: []byte("\x00\x01{{ not a template\x00__NAME__\x02")},
+	})
+	gt.LiteralSubstitutions = map[string]string{
+		"__NAME__": "{{ .Base.projectName }}",
+	}
+
+	opts := newTemplateTestOpts(t)
+
+	_, err := gt.InitNewProject(opts)
+	require.NoError(t, err)
+
+	keystore, err := os.ReadFile(path.Join(getTargetDir(opts.OutputDir, opts), "keystore.bin"))
+	require.NoError(t, err)
+	require.Equal(t, "\x00\x01{{ not a template\x00project\x02", string(keystore))
+}
+
+func TestGT_InitNewProject_Include(t *testing.T) {
+	t.Run("includes rendered files", func(t *testing.T) {
+		gt := newTemplateTestGT(fstest.MapFS{
+			"README.md":            &fstest.MapFile{Data: []byte("# {{ .Base.projectName }}\n\n{{ include \"_partials/license.md\" }}")},
+			"_partials/license.md": &fstest.MapFile{Data: []byte("{{ .Base.projectName }} is licensed under MIT.")},
+		})
+
+		opts := newTemplateTestOpts(t)
+		_, err := gt.InitNewProject(opts)
+		require.NoError(t, err)
+
+		readme, err := os.ReadFile(path.Join(getTargetDir(opts.OutputDir, opts), "README.md"))
+		require.NoError(t, err)
+		require.Equal(t, "# project\n\nproject is licensed under MIT.", string(readme))
+		require.NoDirExists(t, path.Join(getTargetDir(opts.OutputDir, opts), "_partials"), "partials should not be generated")
+	})
+
+	t.Run("error on recursive includes", func(t *testing.T) {
+		gt := newTemplateTestGT(fstest.MapFS{
+			"README.md": &fstest.MapFile{Data: []byte(`{{ include "README.md" }}`)},
+		})
+
+		_, err := gt.InitNewProject(newTemplateTestOpts(t))
+		require.ErrorIs(t, err, gotemplate.ErrMaxIncludeDepth)
+	})
+}
+
+func TestGT_InitNewProject_ClockAndRand(t *testing.T) {
+	newGT := func() *gotemplate.GT {
+		gt := newTemplateTestGT(fstest.MapFS{
+			"LICENSE":   &fstest.MapFile{Data: []byte(`Copyright (c) {{ .Meta.Year }} {{ now | date "2006" }}`)},
+			"secret.md": &fstest.MapFile{Data: []byte(`{{ randAlphaNum 16 }}`)},
+		})
+		gt.Clock = func() time.Time { return time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC) }
+		gt.Rand = rand.New(rand.NewSource(1))
+
+		return gt
+	}
+
+	generate := func(gt *gotemplate.GT) string {
+		opts := newTemplateTestOpts(t)
+
+		_, err := gt.InitNewProject(opts)
+		require.NoError(t, err)
+
+		license, err := os.ReadFile(path.Join(getTargetDir(opts.OutputDir, opts), "LICENSE"))
+		require.NoError(t, err)
+		require.Equal(t, "Copyright (c) 2001 2001", string(license))
+
+		secret, err := os.ReadFile(path.Join(getTargetDir(opts.OutputDir, opts), "secret.md"))
+		require.NoError(t, err)
+		require.Len(t, secret, 16)
+
+		return string(secret)
+	}
+
+	require.Equal(t, generate(newGT()), generate(newGT()), "same random source should render the same output")
+}
+
+func TestGT_InitNewProject_MaxFileSize(t *testing.T) {
+	gt := newTemplateTestGT(fstest.MapFS{
+		"README.md": &fstest.MapFile{Data: []byte("# {{ .Base.projectName }}")},
+		"huge.txt":  &fstest.MapFile{Data: []byte(`{{ range until 1000 }}some text{{ end }}`)},
+	})
+	gt.MaxFileSize = 100
+
+	opts := newTemplateTestOpts(t)
+
+	_, err := gt.InitNewProject(opts)
+	require.ErrorIs(t, err, gotemplate.ErrFileTooLarge)
+	require.Contains(t, err.Error(), "huge.txt")
+
+	_, err = os.Stat(getTargetDir(opts.OutputDir, opts))
+	require.ErrorIs(t, err, os.ErrNotExist, "project should be removed on error")
+}
+
+func TestGT_OutputHash(t *testing.T) {
+	gt := newTemplateTestGT(fstest.MapFS{
+		"README.md":      &fstest.MapFile{Data: []byte("# {{ .Base.projectName }}")},
+		"docs/readme.md": &fstest.MapFile{Data: []byte("{{ .Base.moduleName }}")},
+	})
+
+	hash, err := gt.OutputHash(newTemplateTestValues("project"))
+	require.NoError(t, err)
+	require.NotEmpty(t, hash)
+
+	sameHash, err := gt.OutputHash(newTemplateTestValues("project"))
+	require.NoError(t, err)
+	require.Equal(t, hash, sameHash)
+
+	otherHash, err := gt.OutputHash(newTemplateTestValues("other"))
+	require.NoError(t, err)
+	require.NotEqual(t, hash, otherHash)
+
+	// values that don't change any file don't change the hash
+	unusedValues := newTemplateTestValues("project")
+	unusedValues.Base["unused"] = "value"
+	unusedHash, err := gt.OutputHash(unusedValues)
+	require.NoError(t, err)
+	require.Equal(t, hash, unusedHash)
+
+	// files removed by postHooks are not part of the hash
+	withRemovedFile := newTemplateTestGT(fstest.MapFS{
+		"README.md":      &fstest.MapFile{Data: []byte("# {{ .Base.projectName }}")},
+		"docs/readme.md": &fstest.MapFile{Data: []byte("{{ .Base.moduleName }}")},
+		"Dockerfile":     &fstest.MapFile{Data: []byte("FROM scratch")},
+	})
+	withRemovedFile.Options.Base[0] = gotemplate.NewOption("projectName", "description", gotemplate.StaticValue("project"),
+		gotemplate.WithPosthook(func(_ interface{}, _ *gotemplate.OptionValues, targetDir string) error {
+			return os.Remove(path.Join(targetDir, "Dockerfile"))
+		}),
+	)
+	removedHash, err := withRemovedFile.OutputHash(newTemplateTestValues("project"))
+	require.NoError(t, err)
+	require.Equal(t, hash, removedHash)
+}
+
+func TestGT_InitNewProject_DataProviders(t *testing.T) {
+	templateFS := fstest.MapFS{
+		"README.md": &fstest.MapFile{Data: []byte("{{ .Meta.custom }}")},
+		"docs.md":   &fstest.MapFile{Data: []byte("{{ .Meta.custom }} {{ .Meta.Year }}")},
+	}
+
+	t.Run("renders provided data", func(t *testing.T) {
+		calls := 0
+		gt := newTemplateTestGT(templateFS)
+		gt.Clock = func() time.Time { return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) }
+		gt.DataProviders = map[string]func() (interface{}, error){
+			"custom": func() (interface{}, error) {
+				calls++
+				return "fixed", nil
+			},
+			"Year": func() (interface{}, error) { return 1985, nil },
+		}
+
+		opts := newTemplateTestOpts(t)
+		_, err := gt.InitNewProject(opts)
+		require.NoError(t, err)
+
+		readme, err := os.ReadFile(path.Join(getTargetDir(opts.OutputDir, opts), "README.md"))
+		require.NoError(t, err)
+		require.Equal(t, "fixed", string(readme))
+
+		docs, err := os.ReadFile(path.Join(getTargetDir(opts.OutputDir, opts), "docs.md"))
+		require.NoError(t, err)
+		require.Equal(t, "fixed 2020", string(docs), "built-in meta data should not be overridden")
+		require.Equal(t, 1, calls, "provider should only be evaluated once")
+	})
+
+	t.Run("providers are evaluated once for all combinations of a matrix", func(t *testing.T) {
+		calls := 0
+		gt := newTemplateTestGT(templateFS)
+		gt.DataProviders = map[string]func() (interface{}, error){
+			"custom": func() (interface{}, error) {
+				calls++
+				return "fixed", nil
+			},
+		}
+
+		_, err := gt.GenerateMatrix([]*gotemplate.OptionValues{newTemplateTestValues("project"), newTemplateTestValues("project")})
+		require.NoError(t, err)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("error of provider aborts generation", func(t *testing.T) {
+		gt := newTemplateTestGT(templateFS)
+		gt.DataProviders = map[string]func() (interface{}, error){
+			"custom": func() (interface{}, error) { return nil, errors.New("git not found") },
+		}
+
+		opts := newTemplateTestOpts(t)
+		_, err := gt.InitNewProject(opts)
+		require.ErrorContains(t, err, "data provider custom: git not found")
+
+		_, err = os.Stat(getTargetDir(opts.OutputDir, opts))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestGT_InitNewProject_FileHeader(t *testing.T) {
+	gt := newTemplateTestGT(fstest.MapFS{
+		"main.go":          &fstest.MapFile{Data: []byte("package main\n")},
+		"scripts/run.sh":   &fstest.MapFile{Data: []byte("#!/bin/sh\necho run\n")},
+		"config.yml":       &fstest.MapFile{Data: []byte("key: value\n")},
+		"README.md":        &fstest.MapFile{Data: []byte("# {{ .Base.projectName }}\n")},
+		"logo.png.literal": &fstest.MapFile{Data: []byte("binary")},
+		"scripts/Makefile": &fstest.MapFile{Data: []byte("all:\n")},
+	})
+	gt.FileHeader = "Copyright {{ .Base.projectName }}\n\nSPDX-License-Identifier: MIT"
+
+	opts := newTemplateTestOpts(t)
+
+	_, err := gt.InitNewProject(opts)
+	require.NoError(t, err)
+
+	expectedFiles := map[string]string{
+		"main.go":          "// Copyright project\n//\n// SPDX-License-Identifier: MIT\n\npackage main\n",
+		"scripts/run.sh":   "#!/bin/sh\n# Copyright project\n#\n# SPDX-License-Identifier: MIT\n\necho run\n",
+		"config.yml":       "# Copyright project\n#\n# SPDX-License-Identifier: MIT\n\nkey: value\n",
+		"scripts/Makefile": "# Copyright project\n#\n# SPDX-License-Identifier: MIT\n\nall:\n",
+		"README.md":        "# project\n",
+		"logo.png":         "binary",
+	}
+	for name, expected := range expectedFiles {
+		content, err := os.ReadFile(path.Join(getTargetDir(opts.OutputDir, opts), name))
+		require.NoError(t, err)
+		require.Equal(t, expected, string(content), name)
+	}
+}
+
+func TestGT_MissingOptionsForTemplates(t *testing.T) {
+	t.Run("reports references without option", func(t *testing.T) {
+		gt := newTemplateTestGT(fstest.MapFS{
+			"README.md":                &fstest.MapFile{Data: []byte("# {{ .Base.projectName }} {{ .Base.undeclared }}")},
+			"{{ .Base.dirName }}/a.md": &fstest.MapFile{Data: []byte("{{ .Meta.Year }}")},
+			"ci.yml": &fstest.MapFile{Data: []byte(
+				`{{ if .Extensions.ci.unknown }}{{ range .Ecosystems }}{{ $.Extensions.other.option }}{{ end }}{{ end }}` +
+					`{{ with .Extensions.missingCategory }}{{ end }}{{ .Extensions.ci.provider }}`,
+			)},
+			"logo.png.literal": &fstest.MapFile{Data: []byte("{{ .Base.notChecked }}")},
+		})
+		gt.Options.Extensions = []gotemplate.Category{
+			{
+				Name: "ci",
+				Options: []gotemplate.Option{
+					gotemplate.NewOption("provider", "description", gotemplate.StaticValue(1)),
+				},
+			},
+		}
+
+		missing, err := gt.MissingOptionsForTemplates()
+		require.NoError(t, err)
+		require.Equal(t, []string{"ci.unknown", "dirName", "missingCategory", "other.option", "undeclared"}, missing)
+	})
+
+	t.Run("all references of the default template are covered", func(t *testing.T) {
+		missing, err := gotemplate.New().MissingOptionsForTemplates()
+		require.NoError(t, err)
+		require.Empty(t, missing)
+	})
+}
+
+func TestGT_Plan(t *testing.T) {
+	gt := gotemplate.New()
+	gt.Streams.Out = &bytes.Buffer{}
+	gt.Streams.Err = &bytes.Buffer{}
+
+	opts := &gotemplate.NewRepositoryOptions{
+		OutputDir:    t.TempDir(),
+		OptionValues: newTestValues(t, map[string]gotemplate.OptionNameToValue{"docker": {"base": false}}),
+	}
+	plan, err := gt.Plan(opts)
+	require.NoError(t, err)
+
+	require.Equal(t, getTargetDir(opts.OutputDir, opts), plan.TargetDir)
+	require.Contains(t, plan.Remove, "Dockerfile", "docker is disabled")
+	require.Contains(t, plan.Remove, ".dockerignore", "docker is disabled")
+	require.NotContains(t, plan.Create, "Dockerfile")
+	require.Contains(t, plan.Create, "README.md")
+	require.Contains(t, plan.Rename, gotemplate.PlannedRename{From: "gitattributes", To: ".gitattributes"})
+	require.Contains(t, plan.Commands, gotemplate.PlannedCommand{Dir: ".", Args: []string{"git", "init"}})
+	require.Contains(t, plan.Commands, gotemplate.PlannedCommand{
+		Dir:  ".",
+		Args: []string{"go", "mod", "init", "github.com/fake/testing"},
+	})
+
+	_, err = os.Stat(plan.TargetDir)
+	require.ErrorIs(t, err, os.ErrNotExist, "nothing should be written")
+
+	planJSON, err := json.Marshal(plan)
+	require.NoError(t, err)
+	require.Contains(t, string(planJSON), `"remove":[`)
+	require.Contains(t, string(planJSON), `"Dockerfile"`)
+}
+
+func TestGT_GenerateMatrix(t *testing.T) {
+	gt := newTemplateTestGT(fstest.MapFS{
+		"README.md":                           &fstest.MapFile{Data: []byte("# {{ .Base.projectName }}")},
+		"cmd/{{ .Base.projectName }}/main.go": &fstest.MapFile{Data: []byte("package main")},
+		"Dockerfile":                          &fstest.MapFile{Data: []byte("FROM scratch")},
+	})
+	// the Dockerfile is only part of the first project
+	gt.Options.Base[0] = gotemplate.NewOption("projectName", "description", gotemplate.StaticValue("project"),
+		gotemplate.WithPosthook(func(value interface{}, _ *gotemplate.OptionValues, targetDir string) error {
+			if value == "first" {
+				return nil
+			}
+			return os.Remove(path.Join(targetDir, "Dockerfile"))
+		}),
+	)
+
+	combinations := []*gotemplate.OptionValues{newTemplateTestValues("first"), newTemplateTestValues("second")}
+	results, err := gt.GenerateMatrix(combinations)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.Equal(t, combinations[0], results[0].Values)
+	require.Equal(t, []string{"Dockerfile", "README.md", "cmd/first/main.go"}, results[0].Files)
+	require.Equal(t, []string{"README.md", "cmd/second/main.go"}, results[1].Files)
+	require.NotEqual(t, results[0].Hash, results[1].Hash)
+
+	hash, err := gt.OutputHash(combinations[0])
+	require.NoError(t, err)
+	require.Equal(t, hash, results[0].Hash)
+
+	artifact, err := json.Marshal(results)
+	require.NoError(t, err)
+	require.Contains(t, string(artifact), `"files":["Dockerfile","README.md","cmd/first/main.go"]`)
+	require.Contains(t, string(artifact), `"projectName":"second"`)
+}
+
+func TestGT_InitNewProject_PlaceholderImportPath(t *testing.T) {
+	gt := newTemplateTestGT(fstest.MapFS{
+		"main.go": &fstest.MapFile{Data: []byte(`package main
+
+import (
+	"fmt"
+
+	"example.com/placeholder/internal/log"
+)
+
+func main() {
+	fmt.Println(log.Name)
+}
+`)},
+		"internal/log/log.go": &fstest.MapFile{Data: []byte("package log\n\nconst Name = \"log\"\n")},
+	})
+	gt.PlaceholderImportPath = "example.com/placeholder"
+
+	opts := newTemplateTestOpts(t)
+
+	_, err := gt.InitNewProject(opts)
+	require.NoError(t, err)
+
+	mainFile, err := os.ReadFile(path.Join(getTargetDir(opts.OutputDir, opts), "main.go"))
+	require.NoError(t, err)
+	require.Contains(t, string(mainFile), `"github.com/user/project/internal/log"`)
+	require.NotContains(t, string(mainFile), "example.com/placeholder")
+}
+
+func TestGT_InitNewProject_SkipTidy(t *testing.T) {
+	const skipMessage = "Skipping go mod tidy"
+
+	generate := func(t *testing.T, gt *gotemplate.GT) string {
+		out := &bytes.Buffer{}
+		gt.Out = out
+
+		opts := newTemplateTestOpts(t)
+
+		_, err := gt.InitNewProject(opts)
+		require.NoError(t, err)
+
+		return out.String()
+	}
+
+	stdlibTemplate := fstest.MapFS{
+		"main.go": &fstest.MapFile{Data: []byte(`package main
+
+import (
+	"fmt"
+
+	"github.com/user/project/internal/log"
+)
+
+func main() {
+	fmt.Println(log.Name)
+}
+`)},
+		"internal/log/log.go": &fstest.MapFile{Data: []byte("package log\n\nconst Name = \"log\"\n")},
+	}
+
+	t.Run("skips tidy if only the standard library is used", func(t *testing.T) {
+		require.Contains(t, generate(t, newTemplateTestGT(stdlibTemplate)), skipMessage)
+	})
+
+	t.Run("runs tidy if forced", func(t *testing.T) {
+		gt := newTemplateTestGT(stdlibTemplate)
+		gt.ForceTidy = true
+
+		require.NotContains(t, generate(t, gt), skipMessage)
+	})
+
+	t.Run("runs tidy if external packages are imported", func(t *testing.T) {
+		gt := newTemplateTestGT(fstest.MapFS{
+			"main.go": &fstest.MapFile{Data: []byte(`package main
+
+import "github.com/pkg/errors"
+
+func main() {
+	panic(errors.New("error"))
+}
+`)},
+		})
+
+		require.NotContains(t, generate(t, gt), skipMessage)
+	})
+}
+
+func TestGT_InitNewProject_RequiredTools(t *testing.T) {
+	gt := newTemplateTestGT(fstest.MapFS{
+		"README.md": &fstest.MapFile{Data: []byte("# {{ .Base.projectName }}")},
+	})
+	gt.Options.Extensions = []gotemplate.Category{
+		{
+			Name: "integrations",
+			Options: []gotemplate.Option{
+				gotemplate.NewOption(
+					"someIntegration",
+					"description",
+					gotemplate.StaticValue(false),
+					gotemplate.WithRequiredTools("some-tool-that-does-not-exist"),
+				),
+			},
+		},
+	}
+
+	opts := newTemplateTestOpts(t)
+	opts.OptionValues.Extensions = map[string]gotemplate.OptionNameToValue{
+		"integrations": {"someIntegration": true},
+	}
+
+	_, err := gt.InitNewProject(opts)
+	require.ErrorIs(t, err, gotemplate.ErrMissingTools)
+	require.Contains(t, err.Error(), "some-tool-that-does-not-exist")
+
+	_, err = os.Stat(getTargetDir(opts.OutputDir, opts))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestGT_InitNewProject_RemovedFiles(t *testing.T) {
+	gt := newTemplateTestGT(fstest.MapFS{
+		"README.md":                &fstest.MapFile{Data: []byte("# {{ .Base.projectName }}")},
+		"integration/config.yml":   &fstest.MapFile{Data: []byte("key: value")},
+		"integration/settings.yml": &fstest.MapFile{Data: []byte("key: value")},
+	})
+	gt.Options.Extensions = []gotemplate.Category{
+		{
+			Name: "integrations",
+			Options: []gotemplate.Option{
+				gotemplate.NewOption(
+					"integration",
+					"description",
+					gotemplate.StaticValue(false),
+					gotemplate.WithPosthook(func(value interface{}, _ *gotemplate.OptionValues, targetDir string) error {
+						if !value.(bool) {
+							return os.RemoveAll(path.Join(targetDir, "integration"))
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	gt.Out = out
+
+	opts := newTemplateTestOpts(t)
+	opts.OptionValues.Extensions = map[string]gotemplate.OptionNameToValue{
+		"integrations": {"integration": false},
+	}
+
+	result, err := gt.InitNewProject(opts)
+	require.NoError(t, err)
+	require.Equal(t, []string{"integration/config.yml", "integration/settings.yml"}, result.RemovedFiles)
+	require.Contains(t, out.String(), "integration/config.yml")
+	require.Contains(t, out.String(), "integration/settings.yml")
+}
+
+// newTestValues returns the values of testdata/values.yml with overrides applied.
+// The overrides of "base" are applied to the base values, all others to the extension category of the same name.
+// Values that are not overridden are kept.
+func newTestValues(t *testing.T, overrides map[string]gotemplate.OptionNameToValue) *gotemplate.OptionValues {
+	t.Helper()
+
+	testValuesBytes, err := os.ReadFile("./testdata/values.yml")
+	require.NoError(t, err)
+
+	var optionValues gotemplate.OptionValues
+	require.NoError(t, yaml.Unmarshal(testValuesBytes, &optionValues))
+
+	for category, values := range overrides {
+		target := optionValues.Extensions[category]
+		if category == "base" {
+			target = optionValues.Base
+		}
+		if target == nil {
+			target = gotemplate.OptionNameToValue{}
+			optionValues.Extensions[category] = target
+		}
+
+		for name, value := range values {
+			target[name] = value
+		}
+	}
+
+	return &optionValues
+}
+
+// newTemplateTestValues returns values for all options of newTemplateTestGT.
+func newTemplateTestValues(projectName string) *gotemplate.OptionValues {
+	return &gotemplate.OptionValues{
+		Base: gotemplate.OptionNameToValue{
+			"projectName":       projectName,
+			targetDirOptionName: "project",
+			"moduleName":        "github.com/user/project",
+		},
+	}
+}
+
+// newTemplateTestOpts returns options to generate a project with newTemplateTestGT into a temporary directory.
+func newTemplateTestOpts(t *testing.T) *gotemplate.NewRepositoryOptions {
+	t.Helper()
+
+	return &gotemplate.NewRepositoryOptions{
+		OutputDir:    t.TempDir(),
+		OptionValues: newTemplateTestValues("project"),
+	}
+}
+
+// newTemplateTestGT returns a GT with the minimal set of base options needed to
+// generate a project from the given template file system.
+func newTemplateTestGT(templateFS fs.FS) *gotemplate.GT {
+	return &gotemplate.GT{
+		Streams: gotemplate.Streams{
+			Out: &bytes.Buffer{},
+			Err: &bytes.Buffer{},
+		},
+		Options: &gotemplate.Options{
+			Base: []gotemplate.Option{
+				gotemplate.NewOption("projectName", "description", gotemplate.StaticValue("project")),
+				gotemplate.NewOption(targetDirOptionName, "description", gotemplate.StaticValue("project")),
+				gotemplate.NewOption("moduleName", "description", gotemplate.StaticValue("github.com/user/project")),
+			},
+		},
+		FuncMap:    sprig.TxtFuncMap(),
+		TemplateFS: templateFS,
+	}
+}
+
+func writeTestFile(t *testing.T, contents string) string {
+	testFile := path.Join(t.TempDir(), "test.yml")
+	require.NoError(t, os.WriteFile(testFile, []byte(contents), os.ModePerm))
+
+	return testFile
 }
 
 func getTargetDir(dir string, opts *gotemplate.NewRepositoryOptions) string {
@@ -3,15 +3,22 @@ package gotemplate
 import (
 	"bytes"
 	"fmt"
+	"net/mail"
 	"os"
 	"os/exec"
 	"path"
+	"reflect"
 	"regexp"
 	"strings"
 
 	"github.com/schwarzit/go-template/pkg/repos"
 )
 
+const (
+	layoutStandard = "standard"
+	layoutFlat     = "flat"
+)
+
 //nolint:lll // official regex for semver patterns that can't be broken up into multiple lines
 const semverRegex = `^(?P<major>0|[1-9]\d*)\.(?P<minor>0|[1-9]\d*)\.(?P<patch>0|[1-9]\d*)(?:-(?P<prerelease>(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+(?P<buildmetadata>[0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`
 
@@ -25,6 +32,41 @@ func (e *ErrOutOfRange) Error() string {
 	return fmt.Sprintf("%d: value out of range (min: %d, max: %d)", e.Value, e.Min, e.Max)
 }
 
+// ErrNotAllowed indicates that a value is not one of the allowed values of an option.
+type ErrNotAllowed struct {
+	Value   interface{}
+	Allowed []string
+}
+
+func (e *ErrNotAllowed) Error() string {
+	return fmt.Sprintf("%v: value not allowed (allowed: %s)", e.Value, strings.Join(e.Allowed, ", "))
+}
+
+// ErrInvalidDefaults contains all errors that were found while validating the default values of options.
+// The keys are the names of the options, extension options are named "<category>.<option>".
+type ErrInvalidDefaults struct {
+	Names  []string
+	Errors map[string]error
+}
+
+func (e *ErrInvalidDefaults) Error() string {
+	messages := make([]string, 0, len(e.Names))
+	for _, name := range e.Names {
+		messages = append(messages, fmt.Sprintf("%s: %s", name, e.Errors[name]))
+	}
+
+	return fmt.Sprintf("invalid default values: %s", strings.Join(messages, "; "))
+}
+
+func (e *ErrInvalidDefaults) add(name string, err error) {
+	if e.Errors == nil {
+		e.Errors = map[string]error{}
+	}
+
+	e.Names = append(e.Names, name)
+	e.Errors[name] = err
+}
+
 // ErrInvalidPattern indicates that an error occurred while matching
 // a value with a pattern.
 // The pattern as well as a description for the pattern is included in the error message.
@@ -38,6 +80,25 @@ func (e *ErrInvalidPattern) Error() string {
 	return fmt.Sprintf("%s: invalid pattern (expected %s (pattern: %s))", e.Value, e.Description, e.Pattern)
 }
 
+// ErrInvalidSubmodule indicates that a directory in the list of submodules can't be used.
+type ErrInvalidSubmodule struct {
+	Submodule string
+	Reason    string
+}
+
+func (e *ErrInvalidSubmodule) Error() string {
+	return fmt.Sprintf("%s: invalid submodule (%s)", e.Submodule, e.Reason)
+}
+
+// ErrInvalidEmail indicates that a value is not a valid email address.
+type ErrInvalidEmail struct {
+	Value string
+}
+
+func (e *ErrInvalidEmail) Error() string {
+	return fmt.Sprintf("%s: invalid email address", e.Value)
+}
+
 // Validator is a single method interface that validates that a given value is valid.
 // If any error happens during validation or if the value is not valid an error will be returned.
 type Validator interface {
@@ -73,10 +134,41 @@ type Option struct {
 	// The passed interface contains the value of the option for convenience (technically also contained in optionValues)
 	// targetDir indicates the working directory of the postHook
 	postHook PostHookFunc
+	// onEnable is executed when upgrading an existing project and the option changed from
+	// a disabled (zero) value to an enabled (non-zero) one, e.g. to initialize the config of a new integration.
+	onEnable TransitionHookFunc
+	// onDisable is the counterpart to onEnable and is executed if the option changed from enabled to disabled.
+	onDisable TransitionHookFunc
+	// requiredTools are executables that need to be available in the PATH if the option is enabled.
+	// This is checked before the project is generated.
+	requiredTools []string
+	// transform is applied to every value before it's validated, e.g. to normalize inputs.
+	// If it is not set values are used as they are.
+	transform TransformFunc
+	// allowedValues returns the values that can be chosen for the option.
+	// It's evaluated with the current values since the choices could depend on earlier inputs.
+	// If it is not set or returns no values all values are allowed.
+	allowedValues AllowedValuesFunc
+	// dependsOn contains the names of options ("<category>.<option>" for extensions) that need to be
+	// enabled (set to a non-zero value) for this option to be shown.
+	dependsOn []string
+	// optional base options fall back to their default if they are not set in a file.
+	// This keeps existing files valid if a base option is added, otherwise all base options need to be set.
+	optional bool
 }
 
 type PostHookFunc func(value interface{}, optionValues *OptionValues, targetDir string) error
 
+// TransitionHookFunc is executed if an option's value transitions between enabled and disabled.
+// optionValues contains the new values, targetDir is the directory of the project that is upgraded.
+type TransitionHookFunc func(optionValues *OptionValues, targetDir string) error
+
+// TransformFunc converts a value of an option, e.g. to normalize it.
+type TransformFunc func(value interface{}) interface{}
+
+// AllowedValuesFunc computes the allowed values of an option based on the current values.
+type AllowedValuesFunc func(currentValues *OptionValues) []string
+
 func NewOption(name, description string, defaultValue Valuer, opts ...NewOptionOption) Option {
 	option := Option{
 		name:         name,
@@ -111,6 +203,53 @@ func WithPosthook(postHook PostHookFunc) NewOptionOption {
 	}
 }
 
+func WithOnEnable(onEnable TransitionHookFunc) NewOptionOption {
+	return func(o *Option) {
+		o.onEnable = onEnable
+	}
+}
+
+func WithOnDisable(onDisable TransitionHookFunc) NewOptionOption {
+	return func(o *Option) {
+		o.onDisable = onDisable
+	}
+}
+
+func WithRequiredTools(tools ...string) NewOptionOption {
+	return func(o *Option) {
+		o.requiredTools = tools
+	}
+}
+
+// WithTransform sets a function that is applied to all values of the option before they're validated.
+func WithTransform(transform TransformFunc) NewOptionOption {
+	return func(o *Option) {
+		o.transform = transform
+	}
+}
+
+// WithAllowedValues restricts the option to the given values.
+func WithAllowedValues(values ...string) NewOptionOption {
+	return WithAllowedValuesFunc(func(*OptionValues) []string {
+		return values
+	})
+}
+
+// WithAllowedValuesFunc restricts the option to the values computed by allowedValues.
+func WithAllowedValuesFunc(allowedValues AllowedValuesFunc) NewOptionOption {
+	return func(o *Option) {
+		o.allowedValues = allowedValues
+	}
+}
+
+// WithDependsOn only shows the option if all the given options are enabled, i.e. set to a non-zero value.
+// Extension options are referenced as "<category>.<option>".
+func WithDependsOn(optionNames ...string) NewOptionOption {
+	return func(o *Option) {
+		o.dependsOn = optionNames
+	}
+}
+
 func (s *Option) Name() string {
 	return s.name
 }
@@ -125,8 +264,13 @@ func (s *Option) Default(currentValues *OptionValues) interface{} {
 }
 
 // ShouldDisplay returns a bool value indicating whether the option should be shown or not.
+// Options are not shown if any of the options they depend on is not enabled.
 // If shouldDisplay variable is not set on the option true is returned.
 func (s *Option) ShouldDisplay(currentValues *OptionValues) bool {
+	if _, unmet := s.UnmetDependency(currentValues); unmet {
+		return false
+	}
+
 	if s.shouldDisplay != nil {
 		return s.shouldDisplay.Value(currentValues)
 	}
@@ -134,6 +278,17 @@ func (s *Option) ShouldDisplay(currentValues *OptionValues) bool {
 	return true
 }
 
+// UnmetDependency returns the name of the first option the option depends on that is not enabled in currentValues.
+func (s *Option) UnmetDependency(currentValues *OptionValues) (string, bool) {
+	for _, dependency := range s.dependsOn {
+		if !isEnabled(currentValues.value(dependency)) {
+			return dependency, true
+		}
+	}
+
+	return "", false
+}
+
 // Validate validates the value if a validator is specified.
 func (s *Option) Validate(value interface{}) error {
 	if s.validator != nil {
@@ -143,6 +298,41 @@ func (s *Option) Validate(value interface{}) error {
 	return nil
 }
 
+// Transform applies the registered transform to the value if there is any.
+func (s *Option) Transform(value interface{}) interface{} {
+	if s.transform != nil {
+		return s.transform(value)
+	}
+
+	return value
+}
+
+// AllowedValues returns the values that can be chosen for the option with the given currentValues.
+// If nil is returned all values are allowed.
+func (s *Option) AllowedValues(currentValues *OptionValues) []string {
+	if s.allowedValues != nil {
+		return s.allowedValues(currentValues)
+	}
+
+	return nil
+}
+
+// ValidateAllowed checks that the value is one of the allowed values computed with currentValues.
+func (s *Option) ValidateAllowed(value interface{}, currentValues *OptionValues) error {
+	allowed := s.AllowedValues(currentValues)
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	for _, allowedValue := range allowed {
+		if fmt.Sprint(value) == allowedValue {
+			return nil
+		}
+	}
+
+	return &ErrNotAllowed{Value: value, Allowed: allowed}
+}
+
 // PostHook executes the registered postHook if there is any.
 func (s *Option) PostHook(v interface{}, optionValues *OptionValues, targetDir string) error {
 	if s.postHook != nil {
@@ -152,12 +342,72 @@ func (s *Option) PostHook(v interface{}, optionValues *OptionValues, targetDir s
 	return nil
 }
 
+// MissingTools returns all required tools that can't be found in the PATH.
+// If the option is not enabled with the given value no tools are required.
+func (s *Option) MissingTools(value interface{}) []string {
+	if !isEnabled(value) {
+		return nil
+	}
+
+	var missing []string
+	for _, tool := range s.requiredTools {
+		if _, err := exec.LookPath(tool); err != nil {
+			missing = append(missing, tool)
+		}
+	}
+
+	return missing
+}
+
+// Transition executes the onEnable or onDisable hook if the option's value
+// changed from disabled to enabled or vice versa. Zero values are considered disabled.
+func (s *Option) Transition(previous, current interface{}, optionValues *OptionValues, targetDir string) error {
+	wasEnabled, nowEnabled := isEnabled(previous), isEnabled(current)
+
+	switch {
+	case !wasEnabled && nowEnabled && s.onEnable != nil:
+		return s.onEnable(optionValues, targetDir)
+	case wasEnabled && !nowEnabled && s.onDisable != nil:
+		return s.onDisable(optionValues, targetDir)
+	}
+
+	return nil
+}
+
+func isEnabled(value interface{}) bool {
+	return value != nil && !reflect.ValueOf(value).IsZero()
+}
+
 // Category is used to wrap multiple extensions into one organizational unit.
 // This is to reduce the amount of required user input if certain categories if extensions
 // can be skipped as a category instead of needing to skip all one by one.
 type Category struct {
 	Name    string
 	Options []Option
+	// ShouldDisplay decides whether the category is prompted in interactive mode, e.g. depending on base options.
+	// All options of a category that is not displayed are set to their defaults.
+	// If not set the category is always displayed.
+	ShouldDisplay BoolValuer
+	// Order is used to sort the categories in interactive mode, lower values are prompted first.
+	// Categories with the same order are prompted in the order they are defined in.
+	// If not set the order is 0.
+	Order func(vals *OptionValues) int
+}
+
+func (c *Category) shouldDisplay(vals *OptionValues) bool {
+	if c.ShouldDisplay == nil {
+		return true
+	}
+
+	return c.ShouldDisplay.Value(vals)
+}
+
+func (c *Category) order(vals *OptionValues) int {
+	if c.Order == nil {
+		return 0
+	}
+
+	return c.Order(vals)
 }
 
 // Options is the main struct wrapping the configuration
@@ -167,6 +417,18 @@ type Category struct {
 type Options struct {
 	Base       []Option
 	Extensions []Category
+	// Assertions are checked after all values are loaded to validate options against each other.
+	Assertions []Assertion
+}
+
+// Assertion is a rule across multiple options that loaded values need to fulfill.
+type Assertion struct {
+	// Condition is a template pipeline (without the surrounding braces) that is executed with the
+	// OptionValues and needs to be truthy, e.g. `implies .Base.enableTLS (ne .Base.certPath "")`.
+	// Besides the FuncMap of GT the "implies" function can be used.
+	Condition string
+	// Message describes the rule and is part of the error if the assertion fails.
+	Message string
 }
 
 // OptionValues is a struct mirroring the structure of Options but using maps.
@@ -174,8 +436,8 @@ type Options struct {
 // This makes looking up already supplied option values easier than it would
 // be in the Options struct.
 type OptionValues struct {
-	Base       OptionNameToValue            `yaml:"base"`
-	Extensions map[string]OptionNameToValue `yaml:"extensions"`
+	Base       OptionNameToValue            `yaml:"base" json:"base"`
+	Extensions map[string]OptionNameToValue `yaml:"extensions" json:"extensions"`
 }
 
 func NewOptionValues() *OptionValues {
@@ -187,6 +449,244 @@ func NewOptionValues() *OptionValues {
 
 type OptionNameToValue map[string]interface{}
 
+// Ecosystems returns the package ecosystems (as named by dependabot) that are used in a generated project.
+// It can be used in templates as ".Ecosystems" to configure dependency updates.
+func (v *OptionValues) Ecosystems() []string {
+	// go modules are part of every project
+	ecosystems := []string{"gomod"}
+
+	if docker, _ := v.Extensions["docker"]["base"].(bool); docker {
+		ecosystems = append(ecosystems, "docker")
+	}
+
+	if provider, _ := v.Extensions["ci"]["provider"].(int); provider == 1 {
+		ecosystems = append(ecosystems, "github-actions")
+	}
+
+	return ecosystems
+}
+
+// value returns the value of the option with the given name.
+// Extension options are referenced as "<category>.<option>".
+func (v *OptionValues) value(name string) interface{} {
+	if value, ok := v.Base[name]; ok {
+		return value
+	}
+
+	categoryName, optionName, ok := strings.Cut(name, ".")
+	if !ok {
+		return nil
+	}
+
+	return v.Extensions[categoryName][optionName]
+}
+
+// copy returns a copy of the OptionValues that can be modified without
+// touching the original maps.
+func (v *OptionValues) copy() *OptionValues {
+	valuesCopy := NewOptionValues()
+	if v == nil {
+		return valuesCopy
+	}
+
+	for name, value := range v.Base {
+		valuesCopy.Base[name] = value
+	}
+
+	for category, values := range v.Extensions {
+		valuesCopy.Extensions[category] = OptionNameToValue{}
+		for name, value := range values {
+			valuesCopy.Extensions[category][name] = value
+		}
+	}
+
+	return valuesCopy
+}
+
+// lookup returns the option with the given name.
+// Base options are referenced by their name, extension options as "<category>.<option>".
+func (o *Options) lookup(name string) (*Option, bool) {
+	for i := range o.Base {
+		if o.Base[i].Name() == name {
+			return &o.Base[i], true
+		}
+	}
+
+	categoryName, optionName, ok := strings.Cut(name, ".")
+	if !ok {
+		return nil, false
+	}
+
+	for _, category := range o.Extensions {
+		if category.Name != categoryName {
+			continue
+		}
+
+		for i := range category.Options {
+			if category.Options[i].Name() == optionName {
+				return &category.Options[i], true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// ValidateDefaults checks that all templated defaults (TemplateValue) can be parsed and executed.
+// The templates are executed with the zero values of all options with static or templated defaults,
+// which finds e.g. functions that fail for values of the wrong type.
+// All broken defaults are collected and returned in an ErrInvalidDefaults.
+func (o *Options) ValidateDefaults() error {
+	invalidDefaults := &ErrInvalidDefaults{}
+	zeroValues := o.zeroValues()
+
+	validate := func(name string, option *Option) {
+		templateValue, ok := option.defaultValue.(TemplateValue)
+		if !ok {
+			return
+		}
+
+		if _, err := templateValue.render(zeroValues); err != nil {
+			invalidDefaults.add(name, err)
+		}
+	}
+
+	for i := range o.Base {
+		validate(o.Base[i].Name(), &o.Base[i])
+	}
+
+	for _, category := range o.Extensions {
+		for i := range category.Options {
+			validate(fmt.Sprintf("%s.%s", category.Name, category.Options[i].Name()), &category.Options[i])
+		}
+	}
+
+	if len(invalidDefaults.Names) > 0 {
+		return invalidDefaults
+	}
+
+	return nil
+}
+
+// zeroValues returns OptionValues that contain the zero value of the default's type for all options
+// whose type is known without evaluating the default, which are the ones with static and templated defaults.
+func (o *Options) zeroValues() *OptionValues {
+	zeroValue := func(option *Option) (interface{}, bool) {
+		switch defaultValue := option.defaultValue.(type) {
+		case TemplateValue:
+			return "", true
+		case *Value:
+			if defaultValue.v == nil {
+				return nil, false
+			}
+
+			return reflect.Zero(reflect.TypeOf(defaultValue.v)).Interface(), true
+		default:
+			return nil, false
+		}
+	}
+
+	values := NewOptionValues()
+	for i := range o.Base {
+		if value, ok := zeroValue(&o.Base[i]); ok {
+			values.Base[o.Base[i].Name()] = value
+		}
+	}
+
+	for _, category := range o.Extensions {
+		for i := range category.Options {
+			if value, ok := zeroValue(&category.Options[i]); ok {
+				if values.Extensions[category.Name] == nil {
+					values.Extensions[category.Name] = OptionNameToValue{}
+				}
+
+				values.Extensions[category.Name][category.Options[i].Name()] = value
+			}
+		}
+	}
+
+	return values
+}
+
+// RunTransitionHooks compares the previous values of a project with the current ones
+// and runs the onEnable/onDisable hooks of all options whose value changed accordingly.
+// This is meant to be used when upgrading an existing project in targetDir.
+func (o *Options) RunTransitionHooks(previous, current *OptionValues, targetDir string) error {
+	if previous == nil {
+		previous = NewOptionValues()
+	}
+
+	for i := range o.Base {
+		option := &o.Base[i]
+		name := option.Name()
+		if err := option.Transition(previous.Base[name], current.Base[name], current, targetDir); err != nil {
+			return err
+		}
+	}
+
+	for _, category := range o.Extensions {
+		for i := range category.Options {
+			option := &category.Options[i]
+			name := option.Name()
+			err := option.Transition(
+				previous.Extensions[category.Name][name],
+				current.Extensions[category.Name][name],
+				current,
+				targetDir,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// PendingOptions returns the names of all options that are not yet set in values
+// but would still be prompted for, meaning ShouldDisplay returns true for them.
+// Extension options are returned as "<category>.<option>".
+// Options that are not set are evaluated with their default value, in the same way
+// an interactive session would do if the default was accepted.
+func (o *Options) PendingOptions(values *OptionValues) []string {
+	currentValues := values.copy()
+
+	var pending []string
+	for i := range o.Base {
+		option := &o.Base[i]
+		if _, ok := currentValues.Base[option.Name()]; ok {
+			continue
+		}
+
+		if option.ShouldDisplay(currentValues) {
+			pending = append(pending, option.Name())
+		}
+
+		currentValues.Base[option.Name()] = option.Default(currentValues)
+	}
+
+	for _, category := range o.Extensions {
+		if currentValues.Extensions[category.Name] == nil {
+			currentValues.Extensions[category.Name] = OptionNameToValue{}
+		}
+
+		for i := range category.Options {
+			option := &category.Options[i]
+			if _, ok := currentValues.Extensions[category.Name][option.Name()]; ok {
+				continue
+			}
+
+			if option.ShouldDisplay(currentValues) {
+				pending = append(pending, fmt.Sprintf("%s.%s", category.Name, option.Name()))
+			}
+
+			currentValues.Extensions[category.Name][option.Name()] = option.Default(currentValues)
+		}
+	}
+
+	return pending
+}
+
 // NewOptions returns all of go/template's options.
 func NewOptions(githubTagLister repos.GithubTagLister) *Options { //nolint:funlen,cyclop // Static initialization
 	return &Options{
@@ -248,6 +748,37 @@ The default points to "github.com" but for devops for example it would look sth.
 					"valid semver version string",
 				),
 			},
+			{
+				name:         "layout",
+				defaultValue: StaticValue(layoutStandard),
+				optional:     true,
+				description: `The folder layout of the project.
+Options:
+	standard: the app's main package is in "cmd/<appName>", private code in "internal" and public code in "pkg"
+	flat: the main package is in the project's root, which is handy for small projects`,
+				validator: RegexValidator(
+					fmt.Sprintf("^(%s|%s)$", layoutStandard, layoutFlat),
+					fmt.Sprintf("either %q or %q", layoutStandard, layoutFlat),
+				),
+				postHook: func(v interface{}, _ *OptionValues, targetDir string) error {
+					layoutFiles := map[string][]string{
+						layoutStandard: {"cmd", "internal", "pkg"},
+						layoutFlat:     {"main.go", "log.go"},
+					}
+
+					for layout, files := range layoutFiles {
+						if layout == v.(string) {
+							continue
+						}
+						for _, file := range files {
+							if err := os.RemoveAll(path.Join(targetDir, file)); err != nil {
+								return err
+							}
+						}
+					}
+					return nil
+				},
+			},
 		},
 		Extensions: []Category{
 			{
@@ -345,6 +876,132 @@ Options:
 					},
 				},
 			},
+			{
+				Name: "git",
+				Options: []Option{
+					{
+						name:         "gitattributes",
+						defaultValue: StaticValue(false),
+						description: `Add a .gitattributes file.
+It normalizes line endings and marks generated files, so they are collapsed in diffs and excluded from language stats.`,
+						postHook: func(v interface{}, _ *OptionValues, targetDir string) error {
+							if !v.(bool) {
+								return os.RemoveAll(path.Join(targetDir, ".gitattributes"))
+							}
+							return nil
+						},
+					},
+					{
+						name:         "preCommit",
+						defaultValue: StaticValue(false),
+						description: `Add a config for pre-commit (https://pre-commit.com).
+It contains hooks for golangci-lint and the linters of other enabled integrations (e.g. buf for gRPC, hadolint for Docker).`,
+						postHook: func(v interface{}, _ *OptionValues, targetDir string) error {
+							if !v.(bool) {
+								return os.RemoveAll(path.Join(targetDir, ".pre-commit-config.yaml"))
+							}
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Name: "vscode",
+				Options: []Option{
+					{
+						name:         "base",
+						defaultValue: StaticValue(false),
+						description: `Add VS Code workspace settings.
+This adds a ".vscode/settings.json" with defaults for the Go tooling and a ".vscode/extensions.json" with recommended extensions.`,
+						postHook: func(v interface{}, _ *OptionValues, targetDir string) error {
+							if !v.(bool) {
+								return os.RemoveAll(path.Join(targetDir, ".vscode"))
+							}
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Name: "go",
+				Options: []Option{
+					{
+						name:         "toolchain",
+						defaultValue: StaticValue(""),
+						description: `Set a specific Go toolchain for the project (e.g. "go1.21.0").
+This adds a "toolchain" directive to the "go.mod" file. Leave empty to not set any toolchain.
+Requires Go >= 1.21.`,
+						validator: RegexValidator(
+							`^(go[1-9]\d*\.\d+(\.\d+)?((rc|beta)\d+)?)?$`,
+							`empty or a valid toolchain name like "go1.21.0"`,
+						),
+					},
+					{
+						name:         "submodules",
+						defaultValue: StaticValue(""),
+						description: `Comma separated list of directories (e.g. "libs/a,libs/b") that are initialized as additional Go modules.
+The modules are named "<moduleName>/<directory>" and a "go.work" file that uses all modules is added.
+Leave empty to generate a single module. Requires Go >= 1.18.`,
+						validator: submodulesValidator(),
+					},
+				},
+			},
+			{
+				Name: "docker",
+				Options: []Option{
+					{
+						name:         "base",
+						defaultValue: StaticValue(true),
+						description:  "Add a Dockerfile to build a container image of the app",
+						postHook: func(v interface{}, _ *OptionValues, targetDir string) error {
+							if v.(bool) {
+								return nil
+							}
+							for _, file := range []string{"Dockerfile", ".dockerignore"} {
+								if err := os.RemoveAll(path.Join(targetDir, file)); err != nil {
+									return err
+								}
+							}
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Name: "release",
+				Options: []Option{
+					{
+						name:         "base",
+						defaultValue: StaticValue(false),
+						description: `Automate releases with release-please.
+This adds a config and manifest for release-please that create release PRs with a changelog based on conventional commits.
+If Github is used as CI provider a workflow that runs release-please is added as well.`,
+						postHook: func(v interface{}, _ *OptionValues, targetDir string) error {
+							if v.(bool) {
+								return nil
+							}
+							files := []string{
+								"release-please-config.json",
+								".release-please-manifest.json",
+								".github/workflows/release-please.yml",
+							}
+							for _, file := range files {
+								if err := os.RemoveAll(path.Join(targetDir, file)); err != nil {
+									return err
+								}
+							}
+							return nil
+						},
+					},
+					{
+						name:         "version",
+						defaultValue: StaticValue("0.0.0"),
+						description:  "Version of the latest release, the next release is created based on it",
+						validator:    RegexValidator(`^\d+\.\d+\.\d+$`, "a semantic version without prefix like 1.2.3"),
+						dependsOn:    []string{"release.base"},
+					},
+				},
+			},
 			{
 				Name: "grpc",
 				Options: []Option{
@@ -366,13 +1023,17 @@ Options:
 						name:         "grpcGateway",
 						defaultValue: StaticValue(false),
 						description:  "Extend gRPC configuration with grpc-gateway",
-						shouldDisplay: DynamicBoolValue(func(vals *OptionValues) bool {
-							return vals.Extensions["grpc"]["base"].(bool)
-						}),
+						dependsOn:    []string{"grpc.base"},
 					},
 				},
 			},
 		},
+		Assertions: []Assertion{
+			{
+				Condition: fmt.Sprintf(`implies (eq .Base.layout %q) (not .Extensions.grpc.base)`, layoutFlat),
+				Message:   "the flat layout can't be used with gRPC since the generated code is written to internal/pkg",
+			},
+		},
 	}
 }
 
@@ -426,3 +1087,73 @@ func RegexValidator(pattern, description string) ValidatorFunc {
 		return nil
 	}
 }
+
+// EmailValidator returns a ValidatorFunc that checks that a given value is a plain email address
+// like "marty@future.back". Addresses with a display name ("Marty <marty@future.back>") are not valid.
+// If the value is not valid an ErrInvalidEmail is returned.
+func EmailValidator() ValidatorFunc {
+	return func(value interface{}) error {
+		str := value.(string)
+
+		address, err := mail.ParseAddress(str)
+		if err != nil || address.Name != "" || address.Address != str {
+			return &ErrInvalidEmail{Value: str}
+		}
+
+		return nil
+	}
+}
+
+// submodulesValidator checks that the value is empty or a comma separated list of distinct directories
+// inside the project. "." and ".." are not allowed as path elements since the module would be
+// initialized in the project's root or outside of the project.
+func submodulesValidator() ValidatorFunc {
+	validatePattern := RegexValidator(
+		`^([\w.-]+(/[\w.-]+)*(,[\w.-]+(/[\w.-]+)*)*)?$`,
+		`empty or a comma separated list of relative directories like "libs/a,libs/b"`,
+	)
+
+	return func(value interface{}) error {
+		if err := validatePattern(value); err != nil {
+			return err
+		}
+
+		str := value.(string)
+		if str == "" {
+			return nil
+		}
+
+		seen := map[string]bool{}
+		for _, submodule := range strings.Split(str, ",") {
+			for _, element := range strings.Split(submodule, "/") {
+				if element == "." || element == ".." {
+					return &ErrInvalidSubmodule{Submodule: submodule, Reason: fmt.Sprintf("%q is not allowed as directory", element)}
+				}
+			}
+
+			cleaned := path.Clean(submodule)
+			if seen[cleaned] {
+				return &ErrInvalidSubmodule{Submodule: submodule, Reason: "duplicate"}
+			}
+			seen[cleaned] = true
+		}
+
+		return nil
+	}
+}
+
+// NormalizeEmail is a TransformFunc that lowercases the domain of an email address,
+// since domains are case insensitive. The local part is kept as it is.
+func NormalizeEmail(value interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	at := strings.LastIndex(str, "@")
+	if at < 0 {
+		return value
+	}
+
+	return str[:at] + strings.ToLower(str[at:])
+}
@@ -75,3 +75,213 @@ func Test_RegexValidator(t *testing.T) {
 		})
 	}
 }
+
+var emailValidatorTests = []struct {
+	name        string
+	value       string
+	expectedErr error
+}{
+	{
+		name:        "valid email",
+		value:       "marty@future.back",
+		expectedErr: nil,
+	},
+	{
+		name:        "missing domain",
+		value:       "marty",
+		expectedErr: &ErrInvalidEmail{Value: "marty"},
+	},
+	{
+		name:        "display name",
+		value:       "Marty <marty@future.back>",
+		expectedErr: &ErrInvalidEmail{Value: "Marty <marty@future.back>"},
+	},
+}
+
+func Test_EmailValidator(t *testing.T) {
+	for _, test := range emailValidatorTests {
+		t.Run(test.name, func(t *testing.T) {
+			err := EmailValidator()(test.value)
+			assert.Equal(t, err, test.expectedErr)
+		})
+	}
+}
+
+func Test_NormalizeEmail(t *testing.T) {
+	assert.Equal(t, "Marty.McFly@future.back", NormalizeEmail("Marty.McFly@Future.BACK"))
+	assert.Equal(t, "no email", NormalizeEmail("no email"))
+	assert.Equal(t, 1, NormalizeEmail(1))
+}
+
+func TestOptions_PendingOptions(t *testing.T) {
+	options := &Options{
+		Base: []Option{
+			NewOption("first", "description", StaticValue("first")),
+			NewOption("second", "description", StaticValue("second")),
+			NewOption("hidden", "description", StaticValue(false), WithShouldDisplay(BoolValue(false))),
+		},
+		Extensions: []Category{
+			{
+				Name: "category",
+				Options: []Option{
+					NewOption("enabled", "description", StaticValue(false)),
+					NewOption(
+						"dependent",
+						"description",
+						StaticValue(""),
+						WithShouldDisplay(DynamicBoolValue(func(vals *OptionValues) bool {
+							return vals.Extensions["category"]["enabled"].(bool)
+						})),
+					),
+				},
+			},
+		},
+	}
+
+	values := NewOptionValues()
+	values.Base["first"] = "someValue"
+
+	assert.Equal(t, []string{"second", "category.enabled"}, options.PendingOptions(values))
+	assert.Len(t, values.Base, 1, "passed values should not be modified")
+}
+
+func TestOptions_RunTransitionHooks(t *testing.T) {
+	var enabled, disabled []string
+	newIntegration := func(name string) Option {
+		return NewOption(
+			name,
+			"description",
+			StaticValue(false),
+			WithOnEnable(func(_ *OptionValues, _ string) error {
+				enabled = append(enabled, name)
+				return nil
+			}),
+			WithOnDisable(func(_ *OptionValues, _ string) error {
+				disabled = append(disabled, name)
+				return nil
+			}),
+		)
+	}
+
+	options := &Options{
+		Extensions: []Category{
+			{
+				Name: "integrations",
+				Options: []Option{
+					newIntegration("added"),
+					newIntegration("removed"),
+					newIntegration("unchanged"),
+				},
+			},
+		},
+	}
+
+	previous := &OptionValues{
+		Extensions: map[string]OptionNameToValue{
+			"integrations": {"added": false, "removed": true, "unchanged": true},
+		},
+	}
+	current := &OptionValues{
+		Extensions: map[string]OptionNameToValue{
+			"integrations": {"added": true, "removed": false, "unchanged": true},
+		},
+	}
+
+	assert.NoError(t, options.RunTransitionHooks(previous, current, t.TempDir()))
+	assert.Equal(t, []string{"added"}, enabled)
+	assert.Equal(t, []string{"removed"}, disabled)
+}
+
+func TestOptions_ValidateDefaults(t *testing.T) {
+	t.Run("valid templates", func(t *testing.T) {
+		options := &Options{
+			Base: []Option{
+				NewOption("name", "description", StaticValue("name")),
+				NewOption("slug", "description", TemplateValue(`{{ .Base.name | lower }}`)),
+			},
+		}
+
+		assert.NoError(t, options.ValidateDefaults())
+	})
+
+	t.Run("reports all options with broken templates", func(t *testing.T) {
+		options := &Options{
+			Base: []Option{
+				NewOption("broken", "description", TemplateValue(`{{ .Base.name`)),
+				NewOption("valid", "description", TemplateValue(`{{ .Base.name }}`)),
+			},
+			Extensions: []Category{
+				{
+					Name: "category",
+					Options: []Option{
+						NewOption("alsoBroken", "description", TemplateValue(`{{ end }}`)),
+					},
+				},
+			},
+		}
+
+		err := options.ValidateDefaults()
+
+		var errInvalidDefaults *ErrInvalidDefaults
+		assert.ErrorAs(t, err, &errInvalidDefaults)
+		assert.Equal(t, []string{"broken", "category.alsoBroken"}, errInvalidDefaults.Names)
+	})
+
+	t.Run("reports templates that fail to execute", func(t *testing.T) {
+		options := &Options{
+			Base: []Option{
+				NewOption("number", "description", StaticValue(1)),
+				NewOption("wrongType", "description", TemplateValue(`{{ .Base.number | lower }}`)),
+			},
+			Extensions: []Category{
+				{
+					Name: "category",
+					Options: []Option{
+						NewOption("enabled", "description", StaticValue(false)),
+						NewOption("failing", "description", TemplateValue(`{{ if not .Extensions.category.enabled }}{{ fail "disabled" }}{{ end }}`)),
+					},
+				},
+			},
+		}
+
+		err := options.ValidateDefaults()
+
+		var errInvalidDefaults *ErrInvalidDefaults
+		assert.ErrorAs(t, err, &errInvalidDefaults)
+		assert.Equal(t, []string{"wrongType", "category.failing"}, errInvalidDefaults.Names)
+	})
+}
+
+func TestTemplateValue_Value(t *testing.T) {
+	values := NewOptionValues()
+	values.Base["name"] = "Some Project"
+
+	assert.Equal(t, "some project", TemplateValue(`{{ .Base.name | lower }}`).Value(values))
+	assert.Equal(t, "", TemplateValue(`{{ fail "broken" }}`).Value(values), "the template should not be used as value")
+}
+
+func Test_ToolchainValidator(t *testing.T) {
+	toolchain, ok := NewOptions(nil).lookup("go.toolchain")
+	assert.True(t, ok)
+
+	for _, valid := range []string{"", "go1.21", "go1.21.0", "go1.22rc1"} {
+		assert.NoError(t, toolchain.Validate(valid), valid)
+	}
+
+	for _, invalid := range []string{"1.21.0", "go", "go1.21.0 ", "golang1.21"} {
+		assert.Error(t, toolchain.Validate(invalid), invalid)
+	}
+}
+
+func Test_SubmodulesValidator(t *testing.T) {
+	submodules, ok := NewOptions(nil).lookup("go.submodules")
+	assert.True(t, ok)
+
+	for _, valid := range []string{"", "libs/a", "libs/a,tools", "libs/.hidden", "v1.2"} {
+		assert.NoError(t, submodules.Validate(valid), valid)
+	}
+
+	for _, invalid := range []string{"../evil", "..", ".", "libs/../..", "libs/./a", "a,a", "libs/a,tools,libs/a", "/abs"} {
+		assert.Error(t, submodules.Validate(invalid), invalid)
+	}
+}
@@ -0,0 +1,120 @@
+package gotemplate
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// GenerationPlan describes what InitNewProject would do for the given options without doing it.
+// It can be encoded as JSON, e.g. to be approved before the project is generated.
+// All file paths are relative to the project's root.
+type GenerationPlan struct {
+	// TargetDir is the directory the project would be written to.
+	TargetDir string `json:"targetDir"`
+	// Create contains all files that would be part of the project.
+	Create []string `json:"create"`
+	// Remove contains the files that are rendered but removed by the postHooks of unused integrations.
+	Remove []string `json:"remove"`
+	// Rename contains the files that are written to a different path than they have in the template.
+	Rename []PlannedRename `json:"rename"`
+	// Commands contains the commands that would be run to initialize the project.
+	Commands []PlannedCommand `json:"commands"`
+}
+
+// PlannedRename is a file that is written to another path than it has in the template.
+type PlannedRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// PlannedCommand is a command that would be run to initialize the project.
+type PlannedCommand struct {
+	// Dir is the directory the command would be run in relative to the project's root.
+	Dir  string   `json:"dir"`
+	Args []string `json:"args"`
+}
+
+// Plan computes the GenerationPlan for opts.
+// The project is only rendered into a temporary directory that is removed afterwards,
+// so no files are written to opts.OutputDir and no commands are executed.
+// The same errors as InitNewProject returns are returned before anything is generated,
+// e.g. if the target directory already exists.
+func (gt *GT) Plan(opts *NewRepositoryOptions) (*GenerationPlan, error) {
+	optionValues := opts.OptionValues
+	slug := optionValues.Base["projectSlug"].(string)
+
+	plan := &GenerationPlan{
+		TargetDir: path.Join(opts.OutputDir, slug),
+		Create:    []string{},
+		Remove:    []string{},
+		Rename:    []PlannedRename{},
+		Commands:  []PlannedCommand{},
+	}
+
+	if opts.ArchiveFile == "" {
+		if _, err := os.Stat(plan.TargetDir); !os.IsNotExist(err) {
+			return nil, errors.Wrapf(ErrAlreadyExists, "directory %s", plan.TargetDir)
+		}
+	}
+
+	if err := preHook(gt.Options, optionValues); err != nil {
+		return nil, err
+	}
+
+	ctx, err := gt.newRenderContext()
+	if err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gt-plan-")
+	if err != nil {
+		return nil, err
+	}
+	// ignore error since it's only a temp dir
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	targetDir := filepath.Join(tmpDir, slug)
+	templatePaths := map[string]string{}
+	removedFiles, err := gt.writeProject(ctx, targetDir, optionValues, func(file renderedFile) {
+		if !file.isDir {
+			templatePaths[file.path] = file.templatePath
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	plan.Remove = append(plan.Remove, removedFiles...)
+
+	files, err := listFiles(targetDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for file := range files {
+		plan.Create = append(plan.Create, file)
+		if from, ok := templatePaths[file]; ok && from != file {
+			plan.Rename = append(plan.Rename, PlannedRename{From: from, To: file})
+		}
+	}
+	sort.Strings(plan.Create)
+	sort.Slice(plan.Rename, func(i, j int) bool { return plan.Rename[i].To < plan.Rename[j].To })
+
+	initGit := opts.ArchiveFile == "" || opts.IncludeGit
+	tidy := gt.needsTidy(targetDir, optionValues.Base["moduleName"].(string))
+	for _, cg := range initCommandGroups(targetDir, optionValues, initGit, tidy) {
+		dir, err := filepath.Rel(targetDir, cg.TargetDir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cmd := range cg.Commands {
+			plan.Commands = append(plan.Commands, PlannedCommand{Dir: filepath.ToSlash(dir), Args: cmd.Args})
+		}
+	}
+
+	return plan, nil
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/muesli/termenv"
+	"github.com/pkg/errors"
 	"github.com/schwarzit/go-template/pkg/colors"
 )
 
@@ -37,8 +38,47 @@ func (gt *GT) printWarningf(format string, a ...interface{}) {
 }
 
 func (gt *GT) printOption(opts *Option, optionValues *OptionValues) {
-	gt.printf("%s\n", gt.yellowStyler().Underline().Styled(opts.Description()))
-	gt.printf("%s: (%v) ", gt.cyanStyler().Styled(opts.Name()), opts.Default(optionValues))
+	gt.printf("%s", gt.renderOption(opts, optionValues))
+}
+
+func (gt *GT) renderOption(opts *Option, optionValues *OptionValues) string {
+	return fmt.Sprintf(
+		"%s\n%s",
+		gt.yellowStyler().Underline().Styled(opts.Description()),
+		gt.renderOptionInput(opts, optionValues),
+	)
+}
+
+// printOptionInput prints only the input line of the option's prompt, e.g. to read a value again.
+func (gt *GT) printOptionInput(opts *Option, optionValues *OptionValues) {
+	gt.printf("%s", gt.renderOptionInput(opts, optionValues))
+}
+
+func (gt *GT) renderOptionInput(opts *Option, optionValues *OptionValues) string {
+	name := opts.Name()
+	if allowed := opts.AllowedValues(optionValues); len(allowed) > 0 {
+		name = fmt.Sprintf("%s [%s]", name, strings.Join(allowed, "|"))
+	}
+
+	return fmt.Sprintf("%s: (%v) ", gt.cyanStyler().Styled(name), opts.Default(optionValues))
+}
+
+// RenderPrompt returns the prompt that is shown for the option with the given name
+// when values are loaded interactively, without reading any input.
+// Like in the interactive mode, defaults of the global config are applied.
+// Extension options are referenced as "<category>.<option>".
+func (gt *GT) RenderPrompt(optionName string, values *OptionValues) (string, error) {
+	options, _, err := gt.loadOptions()
+	if err != nil {
+		return "", err
+	}
+
+	option, ok := options.lookup(optionName)
+	if !ok {
+		return "", errors.Wrap(ErrUnknownOption, optionName)
+	}
+
+	return gt.renderOption(option, values), nil
 }
 
 func (gt *GT) printBanner() {
@@ -56,3 +96,14 @@ func (gt *GT) printCategory(category string) {
 	gt.printf("| CATEGORY: %q\n", strings.ToUpper(category))
 	gt.printf(" --\n")
 }
+
+func (gt *GT) printRemovedFiles(files []string) {
+	if len(files) == 0 {
+		return
+	}
+
+	gt.printf("Removed %d files of unused integrations:\n", len(files))
+	for _, file := range files {
+		gt.printf("  - %s\n", file)
+	}
+}
@@ -0,0 +1,113 @@
+package gotemplate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultRecoveryFile returns the path of the recovery file in the user's cache dir
+// (e.g. $XDG_CACHE_HOME/go-template/session.yml).
+func DefaultRecoveryFile() (string, error) {
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(userCacheDir, "go-template", "session.yml"), nil
+}
+
+// loadRecoveredValues loads the values of an interrupted interactive session from gt.RecoveryFile.
+// Empty values are returned if no recovery file is configured or there is none.
+func (gt *GT) loadRecoveredValues() (*OptionValues, error) {
+	recovered := NewOptionValues()
+	if gt.RecoveryFile == "" {
+		return recovered, nil
+	}
+
+	fileBytes, err := os.ReadFile(gt.RecoveryFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return recovered, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(fileBytes, recovered); err != nil {
+		return nil, err
+	}
+
+	// the file might be left by a session for a different project, so the user decides whether to use it
+	gt.printProgressf("Found the following values of an interrupted session in %s:\n", gt.RecoveryFile)
+	if err := DumpOptionValues(gt.Out, recovered); err != nil {
+		return nil, err
+	}
+
+	gt.printf("\nResume the session with these values? [y/N] ")
+	s, err := gt.readStdin()
+	if err != nil {
+		return nil, err
+	}
+	gt.printf("\n")
+
+	switch strings.ToLower(s) {
+	case "y", "yes":
+		return recovered, nil
+	default:
+		return NewOptionValues(), nil
+	}
+}
+
+// recoveredValue returns the value for option from the recovered values if there is one.
+// The value is validated like a value in a file, since the recovery file could be outdated or edited.
+// If it's not valid false is returned and the value needs to be entered again.
+func (gt *GT) recoveredValue(option *Option, recovered OptionNameToValue, optionValues *OptionValues) (interface{}, bool) {
+	val, ok := recovered[option.Name()]
+	if !ok || val == nil {
+		return nil, false
+	}
+
+	if err := gt.validateFileOption(*option, val, *optionValues); err != nil {
+		gt.printWarningf("The recovered value is not valid, please enter it again: %s", err.Error())
+		return nil, false
+	}
+
+	return val, true
+}
+
+// saveRecoveredValues writes the values that are known so far to gt.RecoveryFile.
+// Failures only result in a warning since they shouldn't interrupt the session.
+func (gt *GT) saveRecoveredValues(optionValues *OptionValues) {
+	if gt.RecoveryFile == "" {
+		return
+	}
+
+	var buffer bytes.Buffer
+	err := DumpOptionValues(&buffer, optionValues)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(gt.RecoveryFile), permissionRWX)
+	}
+	if err == nil {
+		err = os.WriteFile(gt.RecoveryFile, buffer.Bytes(), permissionRW)
+	}
+	if err != nil {
+		gt.printWarningf("Could not save the session to %s: %s", gt.RecoveryFile, err.Error())
+	}
+}
+
+// removeRecoveredValues removes gt.RecoveryFile after the session finished successfully.
+func (gt *GT) removeRecoveredValues() error {
+	if gt.RecoveryFile == "" {
+		return nil
+	}
+
+	if err := os.Remove(gt.RecoveryFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,260 @@
+package gotemplate
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// renderedFile is a single file or directory of the rendered template.
+type renderedFile struct {
+	// path is the path relative to the project's root.
+	path string
+	// templatePath is the path of the file in the template it was rendered from.
+	templatePath string
+	isDir        bool
+	content      []byte
+	permissions  fs.FileMode
+}
+
+// renderContext contains the state that is shared by all templates rendered in one run.
+// It's passed through instead of being stored in GT, so a GT can render different templates
+// (e.g. the previous and the current one for an upgrade) without being modified.
+type renderContext struct {
+	// templateFS contains the files of the template that is rendered.
+	templateFS fs.FS
+	// providedData contains the results of gt.DataProviders.
+	providedData map[string]interface{}
+}
+
+// newRenderContext returns a renderContext for the template of gt.
+// The DataProviders are evaluated here, so a renderContext should be created once per public entry point.
+func (gt *GT) newRenderContext() (*renderContext, error) {
+	templateFS, err := gt.templateFS()
+	if err != nil {
+		return nil, err
+	}
+
+	providedData, err := gt.loadProvidedData()
+	if err != nil {
+		return nil, err
+	}
+
+	return &renderContext{templateFS: templateFS, providedData: providedData}, nil
+}
+
+// renderTemplate renders all files of the template with the option values and
+// calls write for every file and directory in lexical order of the template's paths.
+// The partialsDir in the template's root is skipped since its files are only rendered by include.
+// Parent directories are always passed to write before their contents.
+func (gt *GT) renderTemplate(ctx *renderContext, optionValues *OptionValues, write func(file renderedFile) error) error {
+	var (
+		editorConf *editorConfig
+		err        error
+	)
+	if gt.NormalizeEditorConfig {
+		editorConf, err = loadEditorConfig(ctx.templateFS)
+		if err != nil {
+			return err
+		}
+	}
+
+	return fs.WalkDir(ctx.templateFS, ".", func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && filePath == partialsDir {
+			return fs.SkipDir
+		}
+
+		renderedPath, err := gt.executeTemplateString(ctx, filePath, optionValues)
+		if err != nil {
+			return err
+		}
+
+		relativePath := projectFilePath(renderedPath)
+		if d.IsDir() {
+			return write(renderedFile{path: relativePath, isDir: true})
+		}
+
+		content, relativePath, err := gt.renderFile(ctx, filePath, relativePath, optionValues, editorConf)
+		if errors.Is(err, ErrSkipFile) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		filePermissions := fs.FileMode(permissionRW)
+		// files that contain a shebang should be executable
+		if bytes.HasPrefix(bytes.TrimSpace(content), []byte("#!")) {
+			filePermissions = permissionRWX
+		}
+
+		return write(renderedFile{
+			path:         relativePath,
+			templatePath: filePath,
+			content:      content,
+			permissions:  filePermissions,
+		})
+	})
+}
+
+// writeProject renders the template into targetDir, runs the postHooks and rewrites the imports
+// without printing any progress. visit is called for every rendered file or directory.
+// The files that were removed by the postHooks are returned.
+func (gt *GT) writeProject(
+	ctx *renderContext,
+	targetDir string,
+	optionValues *OptionValues,
+	visit func(file renderedFile),
+) ([]string, error) {
+	err := gt.renderTemplate(ctx, optionValues, func(file renderedFile) error {
+		visit(file)
+
+		pathToWrite := path.Join(targetDir, file.path)
+		if file.isDir {
+			return os.MkdirAll(pathToWrite, permissionRWX)
+		}
+
+		return os.WriteFile(pathToWrite, file.content, file.permissions)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	removedFiles, err := postHook(gt.Options, optionValues, targetDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if gt.PlaceholderImportPath != "" {
+		if err := rewriteImports(targetDir, gt.PlaceholderImportPath, optionValues.Base["moduleName"].(string)); err != nil {
+			return nil, err
+		}
+	}
+
+	return removedFiles, nil
+}
+
+// renderProject renders the project like InitNewProject into a temporary directory, including
+// the postHooks and import rewrites but without initializing git and Go modules.
+// It returns all files by their path relative to the project's root.
+func (gt *GT) renderProject(ctx *renderContext, optionValues *OptionValues) (map[string]*renderedFile, error) {
+	tmpDir, err := os.MkdirTemp("", "gt-render-")
+	if err != nil {
+		return nil, err
+	}
+	// ignore error since it's only a temp dir
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	if _, err := gt.writeProject(ctx, tmpDir, optionValues, func(renderedFile) {}); err != nil {
+		return nil, err
+	}
+
+	files := map[string]*renderedFile{}
+	err = filepath.WalkDir(tmpDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(tmpDir, filePath)
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+
+		files[filepath.ToSlash(relativePath)] = &renderedFile{
+			path:        filepath.ToSlash(relativePath),
+			content:     content,
+			permissions: info.Mode().Perm(),
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+// renderFile renders the file at filePath of the template and returns its content as well as the
+// final path in the project. ErrSkipFile is returned if the file should not be written.
+func (gt *GT) renderFile(
+	ctx *renderContext,
+	filePath, relativePath string,
+	optionValues *OptionValues,
+	editorConf *editorConfig,
+) ([]byte, string, error) {
+	fileBytes, err := fs.ReadFile(ctx.templateFS, filePath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var content []byte
+	if strings.HasSuffix(relativePath, literalFileSuffix) {
+		// literal files are not parsed as templates, so also binary files can be used
+		relativePath = strings.TrimSuffix(relativePath, literalFileSuffix)
+		content, err = gt.substituteLiterals(ctx, fileBytes, optionValues)
+		if err != nil {
+			return nil, "", err
+		}
+	} else {
+		data, err := gt.executeTemplateString(ctx, string(fileBytes), optionValues)
+		if err != nil {
+			return nil, "", err
+		}
+
+		content = []byte(data)
+		if gt.FileHeader != "" {
+			header, err := gt.executeTemplateString(ctx, gt.FileHeader, optionValues)
+			if err != nil {
+				return nil, "", err
+			}
+
+			content = addFileHeader(relativePath, content, header)
+		}
+
+		if editorConf != nil {
+			content = editorConf.normalize(relativePath, content)
+		}
+	}
+
+	if gt.AfterRender != nil {
+		content, err = gt.AfterRender(relativePath, content)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	if gt.MaxFileSize > 0 && len(content) > gt.MaxFileSize {
+		return nil, "", errors.Wrapf(ErrFileTooLarge, "template %s: %d bytes (max: %d)", filePath, len(content), gt.MaxFileSize)
+	}
+
+	return content, relativePath, nil
+}
+
+// projectFilePath returns the path a file of the template is written to in the project.
+// Some files can't use their final name in the template since they would take effect
+// in go/template's repo itself.
+func projectFilePath(templatePath string) string {
+	switch templatePath {
+	case "gitattributes":
+		return ".gitattributes"
+	default:
+		return templatePath
+	}
+}
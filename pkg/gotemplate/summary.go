@@ -0,0 +1,59 @@
+package gotemplate
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ConfigSummary gives an overview of the integrations a config file would enable.
+// Extension options are referenced as "<category>.<option>".
+type ConfigSummary struct {
+	// Enabled contains all extension options that are set to a non-zero value.
+	Enabled []string
+	// Disabled contains all extension options that are set to their zero value.
+	Disabled []string
+	// Warnings contains hints about the config that don't prevent generating a project,
+	// e.g. values that are set for unknown options and therefore ignored.
+	Warnings []string
+}
+
+// SummarizeConfig loads the values from configPath in the same way LoadConfigValuesFromFile does
+// and reports which extensions would be enabled or disabled without rendering the project.
+func (gt *GT) SummarizeConfig(configPath string) (*ConfigSummary, error) {
+	optionValues, err := gt.LoadConfigValuesFromFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ConfigSummary{}
+	for _, category := range gt.Options.Extensions {
+		for _, option := range category.Options {
+			name := fmt.Sprintf("%s.%s", category.Name, option.Name())
+			if isEnabled(optionValues.Extensions[category.Name][option.Name()]) {
+				summary.Enabled = append(summary.Enabled, name)
+				continue
+			}
+
+			summary.Disabled = append(summary.Disabled, name)
+		}
+	}
+
+	for name := range optionValues.Base {
+		if _, ok := gt.Options.lookup(name); !ok {
+			summary.Warnings = append(summary.Warnings, fmt.Sprintf("unknown option %s is ignored", name))
+		}
+	}
+
+	for categoryName, values := range optionValues.Extensions {
+		for name := range values {
+			fullName := fmt.Sprintf("%s.%s", categoryName, name)
+			if _, ok := gt.Options.lookup(fullName); !ok {
+				summary.Warnings = append(summary.Warnings, fmt.Sprintf("unknown option %s is ignored", fullName))
+			}
+		}
+	}
+
+	sort.Strings(summary.Warnings)
+
+	return summary, nil
+}
@@ -0,0 +1,141 @@
+package gotemplate
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	lettersAlpha   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	lettersNumeric = "0123456789"
+	// printable ASCII characters from space to tilde
+	asciiFirst = ' '
+	asciiLast  = '~'
+)
+
+// templateData is the data all templates are executed with.
+// Besides the option values (e.g. ".Base.projectName") it contains meta data about
+// the generation (e.g. ".Meta.Year").
+type templateData struct {
+	*OptionValues
+	Meta map[string]interface{}
+}
+
+func (gt *GT) templateData(optionValues *OptionValues, providedData map[string]interface{}) *templateData {
+	appName, _ := optionValues.Base["appName"].(string)
+
+	mainPackage := "./cmd/" + appName
+	if optionValues.Base["layout"] == layoutFlat {
+		mainPackage = "."
+	}
+
+	meta := map[string]interface{}{}
+	for name, value := range providedData {
+		meta[name] = value
+	}
+
+	meta["Year"] = gt.now().Year()
+	// BinaryName is the name of the app's binary
+	meta["BinaryName"] = appName
+	// MainPackage is the path of the app's main package depending on the layout
+	meta["MainPackage"] = mainPackage
+
+	return &templateData{
+		OptionValues: optionValues,
+		Meta:         meta,
+	}
+}
+
+// loadProvidedData evaluates all gt.DataProviders in order of their names
+// and returns the results by name to be used as meta data in templates.
+func (gt *GT) loadProvidedData() (map[string]interface{}, error) {
+	names := make([]string, 0, len(gt.DataProviders))
+	for name := range gt.DataProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	providedData := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		value, err := gt.DataProviders[name]()
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("data provider %s", name))
+		}
+
+		providedData[name] = value
+	}
+
+	return providedData, nil
+}
+
+// now returns the current time of gt.Clock or the real time if it's not set.
+func (gt *GT) now() time.Time {
+	if gt.Clock != nil {
+		return gt.Clock()
+	}
+
+	return time.Now()
+}
+
+// funcMap returns gt.FuncMap extended by the "include" function.
+// An "include" function that is part of gt.FuncMap takes precedence.
+// If gt.Clock or gt.Rand are set, the time and random functions of sprig are replaced
+// by ones using them.
+func (gt *GT) funcMap(ctx *renderContext, optionValues *OptionValues, depth int) template.FuncMap {
+	funcMap := template.FuncMap{
+		"include": func(name string) (string, error) {
+			return gt.include(ctx, name, optionValues, depth+1)
+		},
+	}
+
+	for name, f := range gt.FuncMap {
+		funcMap[name] = f
+	}
+
+	if gt.Clock != nil {
+		funcMap["now"] = gt.now
+	}
+
+	if gt.Rand != nil {
+		for name, f := range randFuncs(gt.Rand) {
+			funcMap[name] = f
+		}
+	}
+
+	return funcMap
+}
+
+// randFuncs returns replacements for sprig's random functions that use r as source.
+func randFuncs(r *rand.Rand) template.FuncMap {
+	randString := func(letters string) func(int) string {
+		return func(count int) string {
+			var buffer bytes.Buffer
+			for i := 0; i < count; i++ {
+				buffer.WriteByte(letters[r.Intn(len(letters))])
+			}
+
+			return buffer.String()
+		}
+	}
+
+	ascii := make([]byte, 0, asciiLast-asciiFirst+1)
+	for c := asciiFirst; c <= asciiLast; c++ {
+		ascii = append(ascii, byte(c))
+	}
+
+	return template.FuncMap{
+		"randInt": func(min, max int) int {
+			return min + r.Intn(max-min)
+		},
+		"randAlpha":    randString(lettersAlpha),
+		"randNumeric":  randString(lettersNumeric),
+		"randAlphaNum": randString(lettersAlpha + lettersNumeric),
+		"randAscii":    randString(string(ascii)),
+	}
+}
@@ -0,0 +1,243 @@
+package gotemplate
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gotemplate "github.com/schwarzit/go-template"
+)
+
+const (
+	defaultReleaseURLFormat = "https://github.com/" + goTemplateGithubOwner + "/" + goTemplateGithubRepo + "/archive/refs/tags/%s.tar.gz"
+	downloadTimeout         = 30 * time.Second
+)
+
+var (
+	_ TemplateSource = EmbeddedTemplateSource{}
+	_ TemplateSource = &GitReleaseTemplateSource{}
+
+	ErrDownloadFailed  = errors.New("downloading template failed")
+	ErrInvalidTemplate = errors.New("invalid template archive")
+	ErrInvalidTag      = errors.New("invalid release tag")
+)
+
+// TemplateSource provides the files of the template that is rendered.
+type TemplateSource interface {
+	TemplateFS() (fs.FS, error)
+}
+
+// EmbeddedTemplateSource provides the template that is embedded in the gt binary.
+type EmbeddedTemplateSource struct{}
+
+func (EmbeddedTemplateSource) TemplateFS() (fs.FS, error) {
+	return fs.Sub(gotemplate.FS, gotemplate.Key)
+}
+
+// GitReleaseTemplateSource provides the template of a tagged release that is
+// downloaded as a tarball from a git host.
+// Downloaded releases are cached in CacheDir, so the network is only used once per tag.
+//
+// Be aware that the options are still defined by the gt binary,
+// so the template of the release should be compatible with them.
+type GitReleaseTemplateSource struct {
+	// Tag is the tag of the release to use.
+	Tag string
+	// URLFormat is the URL of the release's tarball with a "%s" placeholder for the tag.
+	URLFormat string
+	// CacheDir is the directory downloaded releases are stored in.
+	CacheDir string
+	// HTTPClient is used to download the tarball.
+	HTTPClient *http.Client
+}
+
+// NewGitReleaseTemplateSource returns a GitReleaseTemplateSource for the given tag of
+// go/template's Github repo that is cached in the user's cache dir.
+func NewGitReleaseTemplateSource(tag string) (*GitReleaseTemplateSource, error) {
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return &GitReleaseTemplateSource{
+		Tag:        tag,
+		URLFormat:  defaultReleaseURLFormat,
+		CacheDir:   filepath.Join(userCacheDir, "go-template", "releases"),
+		HTTPClient: &http.Client{Timeout: downloadTimeout},
+	}, nil
+}
+
+func (s *GitReleaseTemplateSource) TemplateFS() (fs.FS, error) {
+	releaseDirName, err := s.releaseDirName()
+	if err != nil {
+		return nil, err
+	}
+
+	releaseDir := filepath.Join(s.CacheDir, releaseDirName)
+
+	if _, err := os.Stat(releaseDir); os.IsNotExist(err) {
+		if err := s.download(releaseDir); err != nil {
+			return nil, err
+		}
+	}
+
+	templateDir, err := findTemplateDir(releaseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.DirFS(templateDir), nil
+}
+
+// releaseDirName returns the name of the directory in s.CacheDir the release is cached in.
+// ErrInvalidTag is returned if the tag can't be used as a single directory name
+// (e.g. ".."), since another directory than the release's would be used as template.
+func (s *GitReleaseTemplateSource) releaseDirName() (string, error) {
+	name := strings.ReplaceAll(s.Tag, "/", "_")
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `\:`) {
+		return "", errors.Wrapf(ErrInvalidTag, "%q", s.Tag)
+	}
+
+	return name, nil
+}
+
+// download downloads and extracts the release into releaseDir.
+// The release is extracted into a temp dir first to not leave a broken cache behind on errors.
+func (s *GitReleaseTemplateSource) download(releaseDir string) error {
+	if err := os.MkdirAll(s.CacheDir, permissionRWX); err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp(s.CacheDir, "download-")
+	if err != nil {
+		return err
+	}
+	// ignore error since it's only a temp dir
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	url := fmt.Sprintf(s.URLFormat, s.Tag)
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(ErrDownloadFailed, err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Wrapf(ErrDownloadFailed, "%s: %s", url, resp.Status)
+	}
+
+	if err := extractTarGz(resp.Body, tmpDir); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpDir, releaseDir)
+}
+
+// findTemplateDir returns the template folder in the extracted release.
+// Archives from git hosts usually contain a single top level folder, so the template
+// is searched for in dir as well as one level below.
+func findTemplateDir(dir string) (string, error) {
+	candidates := []string{filepath.Join(dir, gotemplate.Key)}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			candidates = append(candidates, filepath.Join(dir, entry.Name(), gotemplate.Key))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate, nil
+		}
+	}
+
+	return "", errors.Wrapf(ErrInvalidTemplate, "no %s folder found", gotemplate.Key)
+}
+
+func extractTarGz(r io.Reader, targetDir string) error {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return errors.Wrap(ErrInvalidTemplate, err.Error())
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(ErrInvalidTemplate, err.Error())
+		}
+
+		target := filepath.Join(targetDir, filepath.FromSlash(header.Name)) //nolint:gosec // checked below
+		if !strings.HasPrefix(target, filepath.Clean(targetDir)+string(os.PathSeparator)) {
+			return errors.Wrapf(ErrInvalidTemplate, "illegal path %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, permissionRWX); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := extractFile(tarReader, target, fs.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func extractFile(r io.Reader, target string, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), permissionRWX); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, r) //nolint:gosec // the template is a trusted source
+
+	return err
+}
+
+// UseTemplateSource sets the template that is rendered to the one provided by source.
+func (gt *GT) UseTemplateSource(source TemplateSource) error {
+	templateFS, err := source.TemplateFS()
+	if err != nil {
+		return err
+	}
+
+	gt.TemplateFS = templateFS
+
+	return nil
+}
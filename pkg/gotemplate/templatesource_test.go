@@ -0,0 +1,113 @@
+package gotemplate_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/schwarzit/go-template/pkg/gotemplate"
+)
+
+func TestGitReleaseTemplateSource_TemplateFS(t *testing.T) {
+	tarball := createTarGz(t, map[string]string{
+		"go-template-1.0.0/README.md":           "not part of the template",
+		"go-template-1.0.0/_template/README.md": "# {{ .Base.projectName }} from release",
+	})
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/v1.0.0.tar.gz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(tarball)
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	newSource := func() *gotemplate.GitReleaseTemplateSource {
+		return &gotemplate.GitReleaseTemplateSource{
+			Tag:        "v1.0.0",
+			URLFormat:  server.URL + "/%s.tar.gz",
+			CacheDir:   cacheDir,
+			HTTPClient: server.Client(),
+		}
+	}
+
+	t.Run("renders the downloaded release", func(t *testing.T) {
+		gt := newTemplateTestGT(nil)
+		require.NoError(t, gt.UseTemplateSource(newSource()))
+
+		opts := newTemplateTestOpts(t)
+
+		_, err := gt.InitNewProject(opts)
+		require.NoError(t, err)
+
+		readme, err := os.ReadFile(path.Join(getTargetDir(opts.OutputDir, opts), "README.md"))
+		require.NoError(t, err)
+		require.Equal(t, "# project from release", string(readme))
+		require.Equal(t, 1, requests)
+	})
+
+	t.Run("uses the cache if the release was downloaded before", func(t *testing.T) {
+		templateFS, err := newSource().TemplateFS()
+		require.NoError(t, err)
+
+		readme, err := fs.ReadFile(templateFS, "README.md")
+		require.NoError(t, err)
+		require.Contains(t, string(readme), "from release")
+		require.Equal(t, 1, requests, "no further request should be made")
+	})
+
+	t.Run("error if tag is not a valid directory name", func(t *testing.T) {
+		for _, tag := range []string{"", ".", "..", `..\..`, "C:"} {
+			source := newSource()
+			source.Tag = tag
+
+			_, err := source.TemplateFS()
+			require.ErrorIs(t, err, gotemplate.ErrInvalidTag, tag)
+		}
+		require.Equal(t, 1, requests, "no request should be made")
+	})
+
+	t.Run("error if release does not exist", func(t *testing.T) {
+		source := newSource()
+		source.Tag = "v2.0.0"
+
+		_, err := source.TemplateFS()
+		require.ErrorIs(t, err, gotemplate.ErrDownloadFailed)
+	})
+}
+
+func createTarGz(t *testing.T, files map[string]string) []byte {
+	buffer := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for name, content := range files {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})
+		require.NoError(t, err)
+
+		_, err = tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+
+	return buffer.Bytes()
+}
@@ -0,0 +1,159 @@
+package gotemplate
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// UpgradeOptions configure how an existing project is upgraded by UpgradeProject.
+type UpgradeOptions struct {
+	// ProjectDir is the root directory of the project that is upgraded.
+	ProjectDir string
+	// PreviousValues are the values the project was generated with.
+	// If not set there is no previous version of the project to merge with, so all files are handled as new ones
+	// and files that already exist with different content are preserved.
+	PreviousValues *OptionValues
+	// PreviousTemplateFS is the template the project was generated with.
+	// If not set the current template is used, so only changes of the values are applied.
+	PreviousTemplateFS fs.FS
+	// OptionValues are the values the project is upgraded to.
+	OptionValues *OptionValues
+	// ForceRegenerate contains globs (as used by path.Match) of files that are always overwritten
+	// with the rendered version regardless of changes in the project, e.g. ".github/workflows/*".
+	// A glob that matches a directory matches all files in it. Invalid globs result in path.ErrBadPattern.
+	ForceRegenerate []string
+}
+
+// UpgradeResult contains the paths (relative to the project's root) of the files an upgrade touched.
+type UpgradeResult struct {
+	// Updated contains the files that were created or changed.
+	Updated []string
+	// Conflicts contains the files that were changed in the project and the template
+	// in the same place. They contain conflict markers that need to be resolved.
+	Conflicts []string
+	// Preserved contains files that were kept as they are since they were changed in the project
+	// and their original version is unknown, as well as files that were deleted in the project.
+	Preserved []string
+}
+
+// UpgradeProject updates an existing project to the current template and values.
+// The project is rendered with the previous and the new values (and templates) in memory.
+// Files that were not changed in the project are replaced with the new version,
+// other changes are merged with ThreeWayMerge. Files matching opts.ForceRegenerate are always overwritten.
+// Afterwards the onEnable/onDisable hooks of all options whose value changed are run.
+// Files that are not part of the template anymore are kept and files that were deleted in the project
+// are not created again unless they match opts.ForceRegenerate.
+func (gt *GT) UpgradeProject(opts *UpgradeOptions) (*UpgradeResult, error) {
+	for _, glob := range opts.ForceRegenerate {
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, errors.Wrapf(err, "ForceRegenerate glob %q", glob)
+		}
+	}
+
+	ctx, err := gt.newRenderContext()
+	if err != nil {
+		return nil, err
+	}
+
+	previousCtx := ctx
+	if opts.PreviousTemplateFS != nil {
+		previousCtx = &renderContext{templateFS: opts.PreviousTemplateFS, providedData: ctx.providedData}
+	}
+
+	previous := map[string]*renderedFile{}
+	if opts.PreviousValues != nil {
+		previous, err = gt.renderProject(previousCtx, opts.PreviousValues)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rendered, err := gt.renderProject(ctx, opts.OptionValues)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(rendered))
+	for filePath := range rendered {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	result := &UpgradeResult{}
+	for _, filePath := range paths {
+		if err := gt.upgradeFile(opts, filePath, previous[filePath], rendered[filePath], result); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := gt.Options.RunTransitionHooks(opts.PreviousValues, opts.OptionValues, opts.ProjectDir); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (gt *GT) upgradeFile(opts *UpgradeOptions, filePath string, original, rendered *renderedFile, result *UpgradeResult) error {
+	targetPath := filepath.Join(opts.ProjectDir, filepath.FromSlash(filePath))
+
+	write := func(content []byte) error {
+		result.Updated = append(result.Updated, filePath)
+		if err := os.MkdirAll(filepath.Dir(targetPath), permissionRWX); err != nil {
+			return err
+		}
+
+		return os.WriteFile(targetPath, content, rendered.permissions)
+	}
+
+	forced := matchesAnyGlob(opts.ForceRegenerate, filePath)
+	current, err := os.ReadFile(targetPath)
+	switch {
+	case os.IsNotExist(err) && original != nil && !forced:
+		// the file was part of the project before, so it was deleted on purpose
+		result.Preserved = append(result.Preserved, filePath)
+		return nil
+	case os.IsNotExist(err):
+		return write(rendered.content)
+	case err != nil:
+		return err
+	case bytes.Equal(current, rendered.content):
+		return nil
+	case forced:
+		return write(rendered.content)
+	case original == nil:
+		result.Preserved = append(result.Preserved, filePath)
+		return nil
+	}
+
+	merged, hasConflicts := ThreeWayMerge(original.content, current, rendered.content)
+	if bytes.Equal(merged, current) {
+		return nil
+	}
+
+	if hasConflicts {
+		result.Conflicts = append(result.Conflicts, filePath)
+	}
+
+	return write(merged)
+}
+
+// matchesAnyGlob reports whether filePath or any of its parent directories matches one of the globs.
+// The globs need to be valid patterns, which is checked by UpgradeProject.
+func matchesAnyGlob(globs []string, filePath string) bool {
+	for _, glob := range globs {
+		for candidate := filePath; candidate != "." && candidate != "/"; candidate = path.Dir(candidate) {
+			if matched, err := path.Match(strings.TrimSuffix(glob, "/"), candidate); err == nil && matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,102 @@
+package gotemplate_test
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/schwarzit/go-template/pkg/gotemplate"
+)
+
+func TestGT_UpgradeProject(t *testing.T) {
+	gt := newTemplateTestGT(fstest.MapFS{
+		".github/workflows/main.yml": &fstest.MapFile{Data: []byte("name: {{ .Base.projectName }}\nsteps: []\n")},
+		"README.md":                  &fstest.MapFile{Data: []byte("# readme\n\nsome text\n")},
+		"main.go":                    &fstest.MapFile{Data: []byte("// {{ .Base.projectName }}\npackage main\n\nfunc main() {}\n")},
+		"added.txt":                  &fstest.MapFile{Data: []byte("{{ if eq .Base.projectName \"new\" }}added{{ end }}")},
+		"deleted.txt":                &fstest.MapFile{Data: []byte("{{ .Base.projectName }}")},
+	})
+
+	projectDir := t.TempDir()
+	// the project as it was generated with the previous values and edited by the user afterwards
+	projectFiles := map[string]string{
+		".github/workflows/main.yml": "name: old\nsteps: [custom]\n",
+		"README.md":                  "# readme\n\nsome text edited\n",
+		"main.go":                    "// old\npackage main\n\nfunc main() { run() }\n",
+		"added.txt":                  "",
+	}
+	for name, content := range projectFiles {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(projectDir, name)), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(projectDir, name), []byte(content), os.ModePerm))
+	}
+
+	result, err := gt.UpgradeProject(&gotemplate.UpgradeOptions{
+		ProjectDir:      projectDir,
+		PreviousValues:  newTemplateTestValues("old"),
+		OptionValues:    newTemplateTestValues("new"),
+		ForceRegenerate: []string{".github/workflows/*"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{".github/workflows/main.yml", "added.txt", "main.go"}, result.Updated)
+	require.Empty(t, result.Conflicts)
+	require.Equal(t, []string{"deleted.txt"}, result.Preserved, "files deleted by the user should not be created again")
+	require.NoFileExists(t, filepath.Join(projectDir, "deleted.txt"))
+
+	expectedFiles := map[string]string{
+		// force-listed, so the user's changes are overwritten
+		".github/workflows/main.yml": "name: new\nsteps: []\n",
+		// not changed in the template, so the user's changes are preserved
+		"README.md": "# readme\n\nsome text edited\n",
+		// changes of both sides are merged
+		"main.go":   "// new\npackage main\n\nfunc main() { run() }\n",
+		"added.txt": "added",
+	}
+	for name, expected := range expectedFiles {
+		content, err := os.ReadFile(filepath.Join(projectDir, name))
+		require.NoError(t, err)
+		require.Equal(t, expected, string(content), name)
+	}
+}
+
+func TestGT_UpgradeProject_NoPreviousValues(t *testing.T) {
+	gt := newTemplateTestGT(fstest.MapFS{
+		"README.md": &fstest.MapFile{Data: []byte("# {{ .Base.projectName }}\n")},
+		"main.go":   &fstest.MapFile{Data: []byte("package main\n")},
+		"new.txt":   &fstest.MapFile{Data: []byte("{{ .Base.projectName }}")},
+	})
+
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "README.md"), []byte("# edited\n"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "main.go"), []byte("package main\n"), os.ModePerm))
+
+	result, err := gt.UpgradeProject(&gotemplate.UpgradeOptions{
+		ProjectDir:   projectDir,
+		OptionValues: newTemplateTestValues("new"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"new.txt"}, result.Updated)
+	require.Empty(t, result.Conflicts)
+	require.Equal(t, []string{"README.md"}, result.Preserved, "changed files without a previous version should be kept")
+
+	readme, err := os.ReadFile(filepath.Join(projectDir, "README.md"))
+	require.NoError(t, err)
+	require.Equal(t, "# edited\n", string(readme))
+
+	newFile, err := os.ReadFile(filepath.Join(projectDir, "new.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "new", string(newFile))
+}
+
+func TestGT_UpgradeProject_InvalidGlob(t *testing.T) {
+	gt := newTemplateTestGT(fstest.MapFS{})
+
+	_, err := gt.UpgradeProject(&gotemplate.UpgradeOptions{
+		ProjectDir:      t.TempDir(),
+		ForceRegenerate: []string{".github/[workflows"},
+	})
+	require.ErrorIs(t, err, path.ErrBadPattern)
+}
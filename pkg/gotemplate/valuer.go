@@ -1,8 +1,16 @@
 package gotemplate
 
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
 var (
 	_ Valuer       = &Value{}
 	_ Valuer       = DynamicValue(nil)
+	_ Valuer       = TemplateValue("")
 	_ BoolValuer   = BoolValue(false)
 	_ BoolValuer   = DynamicBoolValue(nil)
 	_ StringValuer = StringValue("")
@@ -31,6 +39,39 @@ func (f DynamicValue) Value(vals *OptionValues) interface{} {
 	return f(vals)
 }
 
+// TemplateValue is a go template string that is rendered with the earlier inputs as data.
+// If the template can't be rendered an empty string is returned, so the template itself is never used as value.
+// Broken templates can be found upfront with Options.ValidateDefaults.
+type TemplateValue string
+
+func (v TemplateValue) Value(vals *OptionValues) interface{} {
+	value, err := v.render(vals)
+	if err != nil {
+		return ""
+	}
+
+	return value
+}
+
+// render executes the template with vals as data.
+func (v TemplateValue) render(vals *OptionValues) (string, error) {
+	tmpl, err := v.parse()
+	if err != nil {
+		return "", err
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, vals); err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+func (v TemplateValue) parse() (*template.Template, error) {
+	return template.New("").Funcs(sprig.TxtFuncMap()).Parse(string(v))
+}
+
 type BoolValuer interface {
 	Value(vals *OptionValues) bool
 }
@@ -0,0 +1,98 @@
+package gotemplate
+
+import (
+	"fmt"
+	"reflect"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/pkg/errors"
+)
+
+// ValuesValidator validates option values against a set of options.
+// In contrast to GT it does not need any streams or template, so it can be used
+// to check values (e.g. in CI or other tools) without setting up the generation.
+type ValuesValidator struct {
+	Options *Options
+	// FuncMap contains the functions that can be used in the assertions of Options.
+	FuncMap template.FuncMap
+}
+
+// NewValuesValidator returns a ValuesValidator for the given options
+// that uses sprig's functions for assertions.
+func NewValuesValidator(options *Options) *ValuesValidator {
+	return &ValuesValidator{
+		Options: options,
+		FuncMap: sprig.TxtFuncMap(),
+	}
+}
+
+// Validate checks the types of the values as well as the validators and allowed values of the options
+// like LoadConfigValuesFromFile, but without setting any defaults or checking whether values have an effect.
+// All base options need to be set unless they are optional, extension options are only validated if they are set.
+// Afterwards all assertions of the options are checked.
+func (v *ValuesValidator) Validate(values *OptionValues) error {
+	for i := range v.Options.Base {
+		option := &v.Options.Base[i]
+		value, ok := values.Base[option.Name()]
+		if !ok && option.optional {
+			continue
+		}
+		if !ok || reflect.ValueOf(value).IsZero() {
+			return errors.Wrap(ErrParameterNotSet, option.Name())
+		}
+
+		if err := validateValue(option, option.Name(), value, values, nil); err != nil {
+			return err
+		}
+	}
+
+	for _, category := range v.Options.Extensions {
+		for i := range category.Options {
+			option := &category.Options[i]
+			value, ok := values.Extensions[category.Name][option.Name()]
+			if !ok {
+				continue
+			}
+
+			if err := validateValue(option, fmt.Sprintf("%s.%s", category.Name, option.Name()), value, values, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return validateAssertions(v.Options.Assertions, v.FuncMap, values)
+}
+
+// validateValue checks the type of the value as well as the option's validator and allowed values.
+// Errors of the validator are formatted with errorFormatter if it's set, otherwise they are
+// wrapped in ErrMalformedInput prefixed with name.
+func validateValue(
+	option *Option,
+	name string,
+	value interface{},
+	values *OptionValues,
+	errorFormatter func(optionName string, err error) error,
+) error {
+	defaultType := reflect.TypeOf(option.Default(values))
+	if valType := reflect.TypeOf(value); valType != defaultType {
+		return &ErrTypeMismatch{
+			Expected: defaultType.Name(),
+			Actual:   valType.Name(),
+		}
+	}
+
+	err := option.Validate(value)
+	if err == nil {
+		err = option.ValidateAllowed(value, values)
+	}
+	if err != nil {
+		if errorFormatter != nil {
+			return errorFormatter(option.Name(), err)
+		}
+
+		return errors.Wrap(ErrMalformedInput, fmt.Sprintf("%s: %s", name, err.Error()))
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package gotemplate_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/schwarzit/go-template/pkg/gotemplate"
+)
+
+func TestValuesValidator_Validate(t *testing.T) {
+	validator := gotemplate.NewValuesValidator(&gotemplate.Options{
+		Base: []gotemplate.Option{
+			gotemplate.NewOption("projectName", "description", gotemplate.StaticValue("project"),
+				gotemplate.WithValidator(gotemplate.RegexValidator(`^[a-z-]+$`, "only lowercase letters and dashes")),
+			),
+		},
+		Extensions: []gotemplate.Category{
+			{
+				Name: "ci",
+				Options: []gotemplate.Option{
+					gotemplate.NewOption("provider", "description", gotemplate.StaticValue(1),
+						gotemplate.WithValidator(gotemplate.RangeValidator(0, 3)),
+					),
+					gotemplate.NewOption("lint", "description", gotemplate.StaticValue(false)),
+				},
+			},
+		},
+		Assertions: []gotemplate.Assertion{
+			{
+				Condition: `implies .Extensions.ci.lint (ne .Extensions.ci.provider 0)`,
+				Message:   "linting requires a CI provider",
+			},
+		},
+	})
+
+	newValues := func(projectName string, ci gotemplate.OptionNameToValue) *gotemplate.OptionValues {
+		return &gotemplate.OptionValues{
+			Base:       gotemplate.OptionNameToValue{"projectName": projectName},
+			Extensions: map[string]gotemplate.OptionNameToValue{"ci": ci},
+		}
+	}
+
+	t.Run("valid values", func(t *testing.T) {
+		require.NoError(t, validator.Validate(newValues("my-project", gotemplate.OptionNameToValue{"provider": 2, "lint": true})))
+	})
+
+	t.Run("unset extension options are not validated", func(t *testing.T) {
+		require.NoError(t, validator.Validate(newValues("my-project", nil)))
+	})
+
+	t.Run("error if base option is not set", func(t *testing.T) {
+		require.ErrorIs(t, validator.Validate(newValues("", nil)), gotemplate.ErrParameterNotSet)
+	})
+
+	t.Run("error if validator fails", func(t *testing.T) {
+		err := validator.Validate(newValues("my-project", gotemplate.OptionNameToValue{"provider": 5}))
+		require.ErrorIs(t, err, gotemplate.ErrMalformedInput)
+		require.Contains(t, err.Error(), "ci.provider")
+	})
+
+	t.Run("error on type mismatch", func(t *testing.T) {
+		var errTypeMismatch *gotemplate.ErrTypeMismatch
+		require.ErrorAs(t, validator.Validate(newValues("my-project", gotemplate.OptionNameToValue{"lint": "true"})), &errTypeMismatch)
+	})
+
+	t.Run("error if assertion fails", func(t *testing.T) {
+		err := validator.Validate(newValues("my-project", gotemplate.OptionNameToValue{"provider": 0, "lint": true}))
+		require.ErrorIs(t, err, gotemplate.ErrAssertionFailed)
+	})
+}